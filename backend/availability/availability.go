@@ -0,0 +1,138 @@
+package availability
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrBooked is returned when another active placement or an hourly visit of
+// the housekeeper overlaps the dates of a hiring
+var ErrBooked = errors.New("housekeeper already has work on those dates")
+
+// ActiveHiringStatuses are the statuses of placements that occupy a housekeeper
+var ActiveHiringStatuses = []models.HiringStatus{models.Approved}
+
+// Book marks the housekeeper of hiring as unavailable, failing with ErrBooked
+// when another active placement or an hourly visit overlaps its dates. It must run inside a
+// transaction: the housekeeper is written before the check, so concurrent
+// bookings of the same housekeeper conflict, and the transaction that loses is
+// retried and then sees the other hiring.
+func Book(ctx context.Context, hiring models.Hiring) error {
+	_, err := config.DB.Collection("housekeepers").UpdateOne(ctx,
+		bson.M{"_id": hiring.HousekeeperID},
+		bson.M{"$set": bson.M{"is_available": false}},
+	)
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := Conflicts(ctx, hiring)
+	if err != nil {
+		return err
+	}
+	if conflicts {
+		return ErrBooked
+	}
+	return nil
+}
+
+// Conflicts reports whether another active placement or a scheduled hourly
+// visit of the housekeeper of hiring overlaps its dates. A placement occupies
+// the housekeeper from its start date until its end date, or indefinitely
+// when it has none.
+func Conflicts(ctx context.Context, hiring models.Hiring) (bool, error) {
+	placed, err := placementDuring(ctx, hiring.HousekeeperID, hiring.ID, hiring.StartDate, hiring.EndDate)
+	if err != nil || placed {
+		return placed, err
+	}
+
+	visits := bson.M{
+		"housekeeper_id": hiring.HousekeeperID,
+		"status":         models.OccurrenceScheduled,
+		"ends_at":        bson.M{"$gt": hiring.StartDate},
+	}
+	if !hiring.EndDate.IsZero() {
+		visits["starts_at"] = bson.M{"$lt": hiring.EndDate}
+	}
+	count, err := config.DB.Collection("booking_occurrences").CountDocuments(ctx, visits)
+	return count > 0, err
+}
+
+// PlacementDuring reports whether an active placement occupies the
+// housekeeper at any time between start and end
+func PlacementDuring(ctx context.Context, housekeeperID primitive.ObjectID, start, end time.Time) (bool, error) {
+	return placementDuring(ctx, housekeeperID, primitive.NilObjectID, start, end)
+}
+
+// placementDuring is PlacementDuring leaving out the hiring excludeID
+func placementDuring(ctx context.Context, housekeeperID, excludeID primitive.ObjectID, start, end time.Time) (bool, error) {
+	filter := placementFilter(start, end)
+	filter["_id"] = bson.M{"$ne": excludeID}
+	filter["housekeeper_id"] = housekeeperID
+
+	count, err := config.DB.Collection("hirings").CountDocuments(ctx, filter)
+	return count > 0, err
+}
+
+// PlacedDuring returns the housekeepers an active placement occupies at any
+// time between start and end
+func PlacedDuring(ctx context.Context, start, end time.Time) ([]interface{}, error) {
+	return config.DB.Collection("hirings").Distinct(ctx, "housekeeper_id", placementFilter(start, end))
+}
+
+// placementFilter matches the active placements overlapping start to end. A
+// zero end means the window never ends.
+func placementFilter(start, end time.Time) bson.M {
+	filter := bson.M{
+		"status":       bson.M{"$in": ActiveHiringStatuses},
+		"booking_mode": bson.M{"$ne": models.HourlyBooking},
+		"$or": []bson.M{
+			{"end_date": bson.M{"$exists": false}},
+			{"end_date": bson.M{"$gt": start}},
+		},
+	}
+	if !end.IsZero() {
+		filter["start_date"] = bson.M{"$lt": end}
+	}
+	return filter
+}
+
+// Release makes the housekeeper of hiring available again once none of their
+// other hirings is active
+func Release(ctx context.Context, hiring models.Hiring) error {
+	active, err := countActive(ctx, hiring.HousekeeperID, hiring.ID)
+	if err != nil || active > 0 {
+		return err
+	}
+
+	_, err = config.DB.Collection("housekeepers").UpdateOne(ctx,
+		bson.M{"_id": hiring.HousekeeperID},
+		bson.M{"$set": bson.M{"is_available": true}},
+	)
+	return err
+}
+
+func countActive(ctx context.Context, housekeeperID, excludeHiringID primitive.ObjectID) (int64, error) {
+	return config.DB.Collection("hirings").CountDocuments(ctx, bson.M{
+		"_id":            bson.M{"$ne": excludeHiringID},
+		"housekeeper_id": housekeeperID,
+		"status":         bson.M{"$in": ActiveHiringStatuses},
+		"booking_mode":   bson.M{"$ne": models.HourlyBooking},
+	})
+}
+
+// IsActive reports whether a hiring in status occupies its housekeeper
+func IsActive(status models.HiringStatus) bool {
+	for _, active := range ActiveHiringStatuses {
+		if status == active {
+			return true
+		}
+	}
+	return false
+}
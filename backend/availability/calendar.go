@@ -0,0 +1,88 @@
+package availability
+
+import (
+	"backend/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// noSchedule matches housekeepers who never set a weekly schedule, who are
+// treated as available every day
+var noSchedule = bson.M{"availability.weekly.0": bson.M{"$exists": false}}
+
+// OnDate returns a housekeeper filter matching those whose calendar lets them
+// work on day, which must be a UTC midnight: the weekly schedule or an
+// available exception covers it, and no unavailable exception does
+func OnDate(day time.Time) bson.M {
+	covering := func(available bool) bson.M {
+		return bson.M{"$elemMatch": bson.M{
+			"available": available,
+			"from":      bson.M{"$lte": day},
+			"to":        bson.M{"$gte": day},
+		}}
+	}
+
+	return bson.M{"$and": bson.A{
+		bson.M{"$or": bson.A{
+			noSchedule,
+			bson.M{"availability.weekly.day": models.WeekdayOf(day)},
+			bson.M{"availability.exceptions": covering(true)},
+		}},
+		bson.M{"availability.exceptions": bson.M{"$not": covering(false)}},
+	}}
+}
+
+// OnDays returns a housekeeper filter matching those whose weekly schedule
+// covers every one of days
+func OnDays(days []models.Weekday) bson.M {
+	return bson.M{"$or": bson.A{
+		noSchedule,
+		bson.M{"availability.weekly.day": bson.M{"$all": days}},
+	}}
+}
+
+// Covers reports whether a calendar lets the housekeeper work from start to
+// end. The visit must fall on one local day; an unavailable exception on that
+// day rules it out, an available one allows the whole day, and otherwise a
+// weekly slot on that weekday must contain it. A calendar without a weekly
+// schedule allows any time.
+func Covers(calendar *models.Availability, start, end time.Time) bool {
+	if calendar == nil {
+		return true
+	}
+
+	local := start.In(time.Local)
+	// Exceptions are stored as UTC midnights of their dates
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	available := false
+	for _, exception := range calendar.Exceptions {
+		if day.Before(exception.From) || day.After(exception.To) {
+			continue
+		}
+		if !exception.Available {
+			return false
+		}
+		available = true
+	}
+	if available || len(calendar.Weekly) == 0 {
+		return true
+	}
+
+	localEnd := end.In(time.Local)
+	if localEnd.YearDay() != local.YearDay() || localEnd.Year() != local.Year() {
+		return false
+	}
+	startMinute := local.Hour()*60 + local.Minute()
+	endMinute := localEnd.Hour()*60 + localEnd.Minute()
+	for _, slot := range calendar.Weekly {
+		if slot.Day != models.WeekdayOf(local) {
+			continue
+		}
+		slotStart, slotEnd, err := slot.Minutes()
+		if err == nil && slotStart <= startMinute && endMinute <= slotEnd {
+			return true
+		}
+	}
+	return false
+}
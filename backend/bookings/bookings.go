@@ -0,0 +1,149 @@
+package bookings
+
+import (
+	"backend/availability"
+	"backend/config"
+	"backend/models"
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	// ErrConflict is returned when a visit overlaps another scheduled visit
+	// or an active placement of the housekeeper
+	ErrConflict = errors.New("housekeeper has other work at that time")
+	// ErrUnavailable is returned when a visit falls outside the housekeeper's
+	// availability calendar
+	ErrUnavailable = errors.New("housekeeper is not available at that time")
+)
+
+// Occurrences expands a hiring's booking into its visits, without IDs
+func Occurrences(hiring models.Hiring) []models.Occurrence {
+	booking := hiring.Booking
+	if booking == nil {
+		return nil
+	}
+
+	count, step := 1, 0
+	switch booking.Recurrence {
+	case models.Weekly:
+		count, step = booking.Occurrences, 7
+	case models.Biweekly:
+		count, step = booking.Occurrences, 14
+	}
+
+	duration := time.Duration(booking.DurationMinutes) * time.Minute
+	// Rounded to cents so visits add up to what the employer is shown
+	amount := math.Round(booking.HourlyRate*duration.Hours()*100) / 100
+
+	occurrences := make([]models.Occurrence, count)
+	for i := range occurrences {
+		start := booking.FirstStart.AddDate(0, 0, i*step)
+		occurrences[i] = models.Occurrence{
+			HiringID:      hiring.ID,
+			EmployerID:    hiring.EmployerID,
+			HousekeeperID: hiring.HousekeeperID,
+			Sequence:      i + 1,
+			StartsAt:      start,
+			EndsAt:        start.Add(duration),
+			Amount:        amount,
+			Status:        models.OccurrenceScheduled,
+			UpdatedAt:     time.Now(),
+		}
+	}
+	return occurrences
+}
+
+// Check verifies that every visit of an hourly hiring fits the housekeeper's
+// availability calendar, failing with ErrUnavailable, and does not overlap
+// another scheduled visit or an active placement, failing with ErrConflict
+func Check(ctx context.Context, hiring models.Hiring) error {
+	var housekeeper models.Housekeeper
+	err := config.DB.Collection("housekeepers").FindOne(ctx, bson.M{"_id": hiring.HousekeeperID}).Decode(&housekeeper)
+	if err != nil {
+		return err
+	}
+
+	for _, occurrence := range Occurrences(hiring) {
+		if !availability.Covers(housekeeper.Availability, occurrence.StartsAt, occurrence.EndsAt) {
+			return ErrUnavailable
+		}
+
+		overlapping, err := config.DB.Collection("booking_occurrences").CountDocuments(ctx, bson.M{
+			"hiring_id":      bson.M{"$ne": hiring.ID},
+			"housekeeper_id": hiring.HousekeeperID,
+			"status":         models.OccurrenceScheduled,
+			"starts_at":      bson.M{"$lt": occurrence.EndsAt},
+			"ends_at":        bson.M{"$gt": occurrence.StartsAt},
+		})
+		if err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return ErrConflict
+		}
+
+		placed, err := availability.PlacementDuring(ctx, hiring.HousekeeperID, occurrence.StartsAt, occurrence.EndsAt)
+		if err != nil {
+			return err
+		}
+		if placed {
+			return ErrConflict
+		}
+	}
+	return nil
+}
+
+// Schedule creates the visits of an approved hourly hiring, failing as Check
+// does when one of them cannot take place. It must run inside a transaction:
+// the housekeeper is written before the check, so concurrent approvals
+// conflict and the one retried sees the other.
+func Schedule(ctx context.Context, hiring models.Hiring) error {
+	occurrences := Occurrences(hiring)
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	_, err := config.DB.Collection("housekeepers").UpdateOne(ctx,
+		bson.M{"_id": hiring.HousekeeperID},
+		bson.M{"$currentDate": bson.M{"bookings_updated_at": true}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := Check(ctx, hiring); err != nil {
+		return err
+	}
+
+	documents := make([]interface{}, len(occurrences))
+	for i, occurrence := range occurrences {
+		documents[i] = occurrence
+	}
+	_, err = config.DB.Collection("booking_occurrences").InsertMany(ctx, documents)
+	return err
+}
+
+// CancelRemaining cancels the visits of a hiring that have not happened yet,
+// for when the whole booking ends
+func CancelRemaining(ctx context.Context, hiring models.Hiring, by string) error {
+	now := time.Now()
+	_, err := config.DB.Collection("booking_occurrences").UpdateMany(ctx,
+		bson.M{
+			"hiring_id": hiring.ID,
+			"status":    models.OccurrenceScheduled,
+			"starts_at": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{
+			"status":     models.OccurrenceCancelled,
+			"changed_by": by,
+			"reason":     "Booking ended",
+			"updated_at": now,
+		}},
+	)
+	return err
+}
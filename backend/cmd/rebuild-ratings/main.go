@@ -0,0 +1,29 @@
+// Command rebuild-ratings recomputes every housekeeper's and employer's rating
+// aggregates and ranking score from the review collections.
+//
+// The API maintains the aggregates incrementally as reviews come and go. Run
+// this once after deploying the incremental aggregates, so housekeepers
+// reviewed earlier get their rating sums, and whenever the aggregates are
+// suspected to have drifted from the reviews:
+//
+//	go run ./cmd/rebuild-ratings
+package main
+
+import (
+	"backend/config"
+	"backend/ratings"
+	"context"
+	"log"
+)
+
+func main() {
+	if err := config.ConnectDB(); err != nil {
+		log.Fatal(err)
+	}
+
+	housekeepers, employers, err := ratings.RebuildAll(context.Background())
+	if err != nil {
+		log.Fatalf("Rebuilt %d housekeepers and %d employers before failing: %v", housekeepers, employers, err)
+	}
+	log.Printf("Rebuilt rating aggregates of %d housekeepers and %d employers", housekeepers, employers)
+}
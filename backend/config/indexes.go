@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the indexes the application relies on for
+// uniqueness guarantees. Creating an existing index is a no-op.
+func EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	indexes := map[string][]mongo.IndexModel{
+		"reviews": {
+			{
+				// One review per hiring; older reviews without a hiring are left alone
+				Keys: bson.D{{Key: "hiring_id", Value: 1}},
+				Options: options.Index().SetUnique(true).
+					SetPartialFilterExpression(bson.M{"hiring_id": bson.M{"$exists": true}}),
+			},
+		},
+		"review_reports": {
+			{
+				// A user can report a review, or its reply, only once
+				Keys:    bson.D{{Key: "review_id", Value: 1}, {Key: "reporter_id", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		},
+		"employer_reviews": {
+			{Keys: bson.D{{Key: "hiring_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"contracts": {
+			{Keys: bson.D{{Key: "hiring_id", Value: 1}, {Key: "version", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"deliveries": {
+			// One delivery per hiring; rescheduling updates it
+			{Keys: bson.D{{Key: "hiring_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"payments": {
+			// One payment per hiring and purpose, so a retried request does
+			// not charge twice
+			{Keys: bson.D{{Key: "hiring_id", Value: 1}, {Key: "purpose", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "reference", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"invoices": {
+			{Keys: bson.D{{Key: "hiring_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "number", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "employer_id", Value: 1}, {Key: "issued_at", Value: -1}}},
+		},
+		"salary_records": {
+			// One record per placement and month
+			{Keys: bson.D{{Key: "hiring_id", Value: 1}, {Key: "period", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "period", Value: -1}}},
+		},
+		"webhook_deliveries": {
+			// Pending deliveries are retried in order of when they are due
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		},
+		"payment_webhooks": {
+			{Keys: bson.D{{Key: "provider", Value: 1}, {Key: "event_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"ledger_transactions": {
+			{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "accounts", Value: 1}, {Key: "created_at", Value: -1}}},
+		},
+	}
+
+	for collection, models := range indexes {
+		if _, err := DB.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package contracts
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"backend/pdf"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Data is what contract templates are rendered with
+type Data struct {
+	Hiring      models.Hiring
+	Employer    Employer
+	Housekeeper Housekeeper
+	Version     int
+	Date        time.Time
+}
+
+// Employer holds the employer fields a contract template can use, so that
+// templates never see the password hash or internal scores
+type Employer struct {
+	Name        string
+	Email       string
+	Address     string
+	PhoneNumber string
+}
+
+// Housekeeper holds the housekeeper fields a contract template can use
+type Housekeeper struct {
+	Name           string
+	Age            int
+	Experience     int
+	Category       models.Category
+	EmploymentType models.EmploymentType
+	Skills         []string
+	Location       string
+	PhoneNumber    string
+}
+
+func employerView(employer models.Employer) Employer {
+	return Employer{
+		Name:        employer.Name,
+		Email:       employer.Email,
+		Address:     employer.Address,
+		PhoneNumber: employer.PhoneNumber,
+	}
+}
+
+func housekeeperView(housekeeper models.Housekeeper) Housekeeper {
+	return Housekeeper{
+		Name:           housekeeper.Name,
+		Age:            housekeeper.Age,
+		Experience:     housekeeper.Experience,
+		Category:       housekeeper.Category,
+		EmploymentType: housekeeper.EmploymentType,
+		Skills:         housekeeper.Skills,
+		Location:       housekeeper.Location,
+		PhoneNumber:    housekeeper.PhoneNumber,
+	}
+}
+
+var funcs = template.FuncMap{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "a date to be agreed"
+		}
+		return t.Format("2 January 2006")
+	},
+	"money": Money,
+}
+
+// Money formats an amount in Ethiopian birr, such as "ETB 12,500.00"
+func Money(amount float64) string {
+	s := fmt.Sprintf("%.2f", amount)
+	whole, cents := s[:len(s)-3], s[len(s)-3:]
+	sign := ""
+	if strings.HasPrefix(whole, "-") {
+		sign, whole = "-", whole[1:]
+	}
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	return "ETB " + sign + whole + cents
+}
+
+// Render executes the template body with data
+func Render(body string, data Data) (string, error) {
+	tmpl, err := template.New("contract").Funcs(funcs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// Validate checks that a template body renders, by rendering it with sample
+// data
+func Validate(body string) error {
+	now := time.Now()
+	_, err := Render(body, Data{
+		Hiring: models.Hiring{
+			SalaryOffer:    6000,
+			StartDate:      now,
+			EmploymentType: models.LiveInEmployment,
+			Requirements:   "Cooking and cleaning",
+			Trial:          &models.Trial{Status: models.TrialActive, StartsAt: now, EndsAt: now.AddDate(0, 1, 0)},
+		},
+		Employer:    Employer{Name: "Employer", Address: "Addis Ababa"},
+		Housekeeper: Housekeeper{Name: "Housekeeper", Location: "Addis Ababa"},
+		Version:     1,
+		Date:        now,
+	})
+	return err
+}
+
+// Generate renders a new version of the contract of hiring from the current
+// template and stores it. Earlier versions that were not signed by both
+// parties yet are superseded.
+func Generate(ctx context.Context, hiring models.Hiring) (models.Contract, error) {
+	var employer models.Employer
+	if err := config.DB.Collection("employers").FindOne(ctx, bson.M{"_id": hiring.EmployerID}).Decode(&employer); err != nil {
+		return models.Contract{}, err
+	}
+	var housekeeper models.Housekeeper
+	if err := config.DB.Collection("housekeepers").FindOne(ctx, bson.M{"_id": hiring.HousekeeperID}).Decode(&housekeeper); err != nil {
+		return models.Contract{}, err
+	}
+
+	tmpl, err := currentTemplate(ctx, hiring.EmploymentType)
+	if err != nil {
+		return models.Contract{}, err
+	}
+
+	version, err := nextVersion(ctx, hiring.ID)
+	if err != nil {
+		return models.Contract{}, err
+	}
+
+	now := time.Now()
+	body, err := Render(tmpl.Body, Data{
+		Hiring:      hiring,
+		Employer:    employerView(employer),
+		Housekeeper: housekeeperView(housekeeper),
+		Version:     version,
+		Date:        now,
+	})
+	if err != nil {
+		return models.Contract{}, err
+	}
+
+	sum := sha256.Sum256([]byte(body))
+	contract := models.Contract{
+		HiringID:      hiring.ID,
+		EmployerID:    hiring.EmployerID,
+		HousekeeperID: hiring.HousekeeperID,
+		Version:       version,
+		TemplateID:    tmpl.ID,
+		Body:          body,
+		Hash:          hex.EncodeToString(sum[:]),
+		Status:        models.ContractAwaitingSignatures,
+		Signatures:    []models.ContractSignature{},
+		CreatedAt:     now,
+	}
+	contract.Document = Document(contract)
+
+	err = events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		_, err := config.DB.Collection("contracts").UpdateMany(uow.Context(),
+			bson.M{"hiring_id": hiring.ID, "status": models.ContractAwaitingSignatures},
+			bson.M{"$set": bson.M{"status": models.ContractSuperseded}},
+		)
+		if err != nil {
+			return err
+		}
+
+		result, err := config.DB.Collection("contracts").InsertOne(uow.Context(), contract)
+		if err != nil {
+			return err
+		}
+		contract.ID = result.InsertedID.(primitive.ObjectID)
+
+		return uow.Publish(events.ContractGenerated{Contract: WithoutDocuments(contract)})
+	})
+	return contract, err
+}
+
+// EnsureGenerated generates the first version of the contract of hiring
+// unless one exists already
+func EnsureGenerated(ctx context.Context, hiring models.Hiring) error {
+	count, err := config.DB.Collection("contracts").CountDocuments(ctx, bson.M{"hiring_id": hiring.ID})
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = Generate(ctx, hiring)
+	if mongo.IsDuplicateKeyError(err) {
+		// Generated concurrently
+		return nil
+	}
+	return err
+}
+
+// WithoutDocuments returns contract without its PDFs, to keep them out of
+// events and the outbox
+func WithoutDocuments(contract models.Contract) models.Contract {
+	contract.Document = nil
+	contract.SignedDocument = nil
+	return contract
+}
+
+// Document renders contract as a PDF, with the signatures it has so far
+func Document(contract models.Contract) []byte {
+	doc := pdf.New(fmt.Sprintf("Employment contract v%d", contract.Version))
+	doc.Title("Employment Contract")
+	doc.Note(fmt.Sprintf("Hiring %s - version %d - %s", contract.HiringID.Hex(), contract.Version, contract.CreatedAt.Format("2 January 2006")))
+	doc.Rule()
+
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			doc.Paragraph(strings.Join(paragraph, "\n"))
+			paragraph = nil
+		}
+	}
+	for _, line := range strings.Split(contract.Body, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# "):
+			flush()
+			doc.Heading(strings.TrimPrefix(line, "# "))
+		case strings.TrimSpace(line) == "":
+			flush()
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+
+	doc.Heading("Signatures")
+	for _, party := range []string{"employer", "housekeeper"} {
+		label := partyLabels[party]
+		signature, ok := Signature(contract, party)
+		if !ok {
+			doc.Paragraph(label + ": not signed yet")
+			continue
+		}
+		doc.Paragraph(fmt.Sprintf("%s: signed electronically by %s on %s from IP address %s",
+			label, signature.Name, signature.SignedAt.UTC().Format("2 January 2006 15:04 MST"), signature.IP))
+	}
+	doc.Space(6)
+	doc.Note("Document hash (SHA-256): " + contract.Hash)
+
+	return doc.Bytes()
+}
+
+var partyLabels = map[string]string{"employer": "Employer", "housekeeper": "Housekeeper"}
+
+// Signature returns the signature of party on contract
+func Signature(contract models.Contract, party string) (models.ContractSignature, bool) {
+	for _, signature := range contract.Signatures {
+		if signature.Party == party {
+			return signature, true
+		}
+	}
+	return models.ContractSignature{}, false
+}
+
+func currentTemplate(ctx context.Context, employmentType models.EmploymentType) (models.ContractTemplate, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	for _, filter := range []bson.M{
+		{"employment_type": employmentType},
+		{"employment_type": bson.M{"$exists": false}},
+	} {
+		var tmpl models.ContractTemplate
+		err := config.DB.Collection("contract_templates").FindOne(ctx, filter, opts).Decode(&tmpl)
+		if err == nil {
+			return tmpl, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return models.ContractTemplate{}, err
+		}
+	}
+	return models.ContractTemplate{Name: "Default", Body: defaultTemplate}, nil
+}
+
+func nextVersion(ctx context.Context, hiringID primitive.ObjectID) (int, error) {
+	var latest models.Contract
+	err := config.DB.Collection("contracts").FindOne(ctx,
+		bson.M{"hiring_id": hiringID},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}).SetProjection(bson.M{"version": 1}),
+	).Decode(&latest)
+	if err == mongo.ErrNoDocuments {
+		return 1, nil
+	}
+	return latest.Version + 1, err
+}
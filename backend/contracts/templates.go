@@ -0,0 +1,29 @@
+package contracts
+
+// defaultTemplate is used until staff add templates of their own
+const defaultTemplate = `# Parties
+This employment contract is made on {{date .Date}} between {{.Employer.Name}} of {{.Employer.Address}} (the "Employer") and {{.Housekeeper.Name}} of {{.Housekeeper.Location}} (the "Housekeeper"), through the AGAZH housekeeper placement service.
+
+# Position and start date
+The Employer engages the Housekeeper as a {{if eq .Hiring.EmploymentType "LIVE_IN"}}live-in{{else}}live-out{{end}} housekeeper from {{date .Hiring.StartDate}}.
+{{- if .Hiring.Requirements}}
+
+The duties agreed between the parties are: {{.Hiring.Requirements}}
+{{- end}}
+
+# Salary
+The Employer pays the Housekeeper a monthly salary of {{money .Hiring.SalaryOffer}}, no later than the last working day of each month. Income tax and pension contributions are withheld and paid as required by Ethiopian law.
+{{- if .Hiring.Trial}}
+
+# Trial period
+The first days of the placement, until {{date .Hiring.Trial.EndsAt}}, are a trial period. During the trial period the Employer may ask AGAZH for a replacement housekeeper.
+{{- end}}
+
+# Working time and leave
+Working hours, weekly rest days and annual leave follow the Ethiopian Labour Proclamation No. 1156/2019. {{if eq .Hiring.EmploymentType "LIVE_IN"}}The Employer provides the Housekeeper with a private, safe place to sleep and with daily meals.{{end}}
+
+# Termination
+Either party may end this contract by giving the notice required by law. The party ending the contract informs AGAZH through the app.
+
+# Acceptance
+By signing electronically, each party confirms that they have read and accept this contract.`
@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"backend/config"
+	"backend/events"
 	"backend/models"
+	"backend/ratings"
 	"context"
 	"fmt"
 	"net/http"
@@ -12,6 +14,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -43,15 +46,26 @@ func RegisterHouseKeeper(c *gin.Context) {
 	housekeeper.CreatedAt = time.Now()
 	housekeeper.UpdatedAt = time.Now()
 	housekeeper.Rating = 0
+	housekeeper.RankingScore = ratings.Score(0, 0)
 	housekeeper.IsAvailable = true
 
-	result, err := config.DB.Collection("housekeepers").InsertOne(context.Background(), housekeeper)
+	err = events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("housekeepers").InsertOne(uow.Context(), housekeeper)
+		if err != nil {
+			return err
+		}
+
+		housekeeper.ID = result.InsertedID.(primitive.ObjectID)
+		registered := housekeeper
+		registered.Password = ""
+		return uow.Publish(events.HousekeeperRegistered{Housekeeper: registered})
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Housekeeper registered successfully", "id": result.InsertedID})
+	c.JSON(http.StatusCreated, gin.H{"message": "Housekeeper registered successfully", "id": housekeeper.ID})
 }
 
 // RegisterEmployer godoc
@@ -93,7 +107,7 @@ func RegisterEmployer(c *gin.Context) {
 
 // Login godoc
 // @Summary Logs in a user and generates a JWT token
-// @Description This endpoint logs in either a housekeeper or employer and generates a JWT token
+// @Description This endpoint logs in a housekeeper, employer or admin and generates a JWT token
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -107,7 +121,7 @@ func Login(c *gin.Context) {
 	var credentials struct {
 		Email    string `json:"email" binding:"required,email"`
 		Password string `json:"password" binding:"required"`
-		UserType string `json:"user_type" binding:"required"` // housekeeper or employer
+		UserType string `json:"user_type" binding:"required"` // housekeeper, employer or admin
 	}
 
 	if err := c.ShouldBindJSON(&credentials); err != nil {
@@ -116,9 +130,12 @@ func Login(c *gin.Context) {
 	}
 
 	var collection string
-	if credentials.UserType == "housekeeper" {
+	switch credentials.UserType {
+	case "housekeeper":
 		collection = "housekeepers"
-	} else {
+	case "admin":
+		collection = "admins"
+	default:
 		collection = "employers"
 	}
 
@@ -162,3 +179,22 @@ func Login(c *gin.Context) {
 	})
 
 }
+
+// currentUser returns the ID and type of the user authenticated by AuthMiddleware
+func currentUser(c *gin.Context) (primitive.ObjectID, string, bool) {
+	userID, _ := c.Get("userID")
+	userType, _ := c.Get("userType")
+
+	idString, ok := userID.(string)
+	if !ok {
+		return primitive.NilObjectID, "", false
+	}
+
+	id, err := primitive.ObjectIDFromHex(idString)
+	if err != nil {
+		return primitive.NilObjectID, "", false
+	}
+
+	typeString, _ := userType.(string)
+	return id, typeString, true
+}
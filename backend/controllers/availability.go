@@ -0,0 +1,286 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxExceptionDays caps how long a single availability exception can last
+const maxExceptionDays = 366
+
+// GetAvailability godoc
+// @Summary Fetches a housekeeper's availability
+// @Description This endpoint retrieves the weekly schedule and date exceptions of a housekeeper
+// @Tags availability
+// @Produce json
+// @Param id path string true "Housekeeper ID"
+// @Success 200 {object} models.Availability
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /housekeepers/{id}/availability [get]
+func GetAvailability(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid housekeeper ID"})
+		return
+	}
+
+	var housekeeper models.Housekeeper
+	err = config.DB.Collection("housekeepers").FindOne(context.Background(), bson.M{"_id": id}).Decode(&housekeeper)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Housekeeper not found"})
+		return
+	}
+
+	availability := models.Availability{}
+	if housekeeper.Availability != nil {
+		availability = *housekeeper.Availability
+	}
+	if availability.Weekly == nil {
+		availability.Weekly = []models.WeeklySlot{}
+	}
+	if availability.Exceptions == nil {
+		availability.Exceptions = []models.AvailabilityException{}
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// UpdateWeeklySchedule godoc
+// @Summary Replaces a housekeeper's weekly schedule
+// @Description This endpoint lets housekeepers set the days and hours they are free to work each week. An empty schedule means available every day.
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param id path string true "Housekeeper ID"
+// @Param schedule body models.WeeklyScheduleUpdate true "Weekly schedule"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /housekeepers/{id}/availability [put]
+func UpdateWeeklySchedule(c *gin.Context) {
+	var update models.WeeklyScheduleUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, ok := ownHousekeeperID(c)
+	if !ok {
+		return
+	}
+
+	weekly := update.Weekly
+	if weekly == nil {
+		weekly = []models.WeeklySlot{}
+	}
+	// Times are compared as minutes after midnight: "9:00" is valid input but
+	// sorts after "17:00" as a string
+	for i := range weekly {
+		start, end, err := weekly[i].Minutes()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Slot times must be written as HH:MM"})
+			return
+		}
+		if start >= end {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Each slot must end after it starts"})
+			return
+		}
+		weekly[i].Start = fmt.Sprintf("%02d:%02d", start/60, start%60)
+		weekly[i].End = fmt.Sprintf("%02d:%02d", end/60, end%60)
+	}
+	sort.Slice(weekly, func(i, j int) bool {
+		if weekly[i].Day != weekly[j].Day {
+			return weekdayIndex(weekly[i].Day) < weekdayIndex(weekly[j].Day)
+		}
+		startI, _, _ := weekly[i].Minutes()
+		startJ, _, _ := weekly[j].Minutes()
+		return startI < startJ
+	})
+	for i := 1; i < len(weekly); i++ {
+		start, _, _ := weekly[i].Minutes()
+		_, previousEnd, _ := weekly[i-1].Minutes()
+		if weekly[i].Day == weekly[i-1].Day && start < previousEnd {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Slots on the same day must not overlap"})
+			return
+		}
+	}
+
+	result, err := config.DB.Collection("housekeepers").UpdateOne(context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"availability.weekly": weekly, "availability.updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update weekly schedule"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Housekeeper not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Weekly schedule updated successfully"})
+}
+
+// AddAvailabilityException godoc
+// @Summary Adds an availability exception
+// @Description This endpoint lets housekeepers mark a date range as unavailable, such as a holiday, or as available outside their weekly schedule
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param id path string true "Housekeeper ID"
+// @Param exception body models.AvailabilityExceptionRequest true "Exception"
+// @Success 201 {object} models.AvailabilityException
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /housekeepers/{id}/availability/exceptions [post]
+func AddAvailabilityException(c *gin.Context) {
+	var request models.AvailabilityExceptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, ok := ownHousekeeperID(c)
+	if !ok {
+		return
+	}
+
+	// The binding already checked the format
+	from, _ := time.Parse("2006-01-02", request.From)
+	to, _ := time.Parse("2006-01-02", request.To)
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The exception must end on or after its start date"})
+		return
+	}
+	if to.Sub(from) > maxExceptionDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An exception can last at most a year"})
+		return
+	}
+
+	exception := models.AvailabilityException{
+		ID:        primitive.NewObjectID(),
+		From:      from,
+		To:        to,
+		Available: request.Available,
+		Note:      strings.TrimSpace(request.Note),
+	}
+
+	result, err := config.DB.Collection("housekeepers").UpdateOne(context.Background(),
+		bson.M{"_id": id},
+		bson.M{
+			"$push": bson.M{"availability.exceptions": exception},
+			"$set":  bson.M{"availability.updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add availability exception"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Housekeeper not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, exception)
+}
+
+// DeleteAvailabilityException godoc
+// @Summary Removes an availability exception
+// @Description This endpoint removes one of a housekeeper's availability exceptions
+// @Tags availability
+// @Produce json
+// @Param id path string true "Housekeeper ID"
+// @Param exception_id path string true "Exception ID"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /housekeepers/{id}/availability/exceptions/{exception_id} [delete]
+func DeleteAvailabilityException(c *gin.Context) {
+	id, ok := ownHousekeeperID(c)
+	if !ok {
+		return
+	}
+
+	exceptionID, err := primitive.ObjectIDFromHex(c.Param("exception_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exception ID"})
+		return
+	}
+
+	result, err := config.DB.Collection("housekeepers").UpdateOne(context.Background(),
+		bson.M{"_id": id, "availability.exceptions._id": exceptionID},
+		bson.M{
+			"$pull": bson.M{"availability.exceptions": bson.M{"_id": exceptionID}},
+			"$set":  bson.M{"availability.updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove availability exception"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Availability exception not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Availability exception removed successfully"})
+}
+
+// ownHousekeeperID returns the housekeeper ID in the path when the
+// authenticated user is that housekeeper or an admin, writing the error
+// response itself otherwise
+func ownHousekeeperID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid housekeeper ID"})
+		return primitive.NilObjectID, false
+	}
+
+	userID, userType, ok := currentUser(c)
+	if !ok || !(userType == "admin" || (userType == "housekeeper" && userID == id)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only manage your own availability"})
+		return primitive.NilObjectID, false
+	}
+
+	return id, true
+}
+
+// parseWeekdays parses a comma separated list of weekday names in any case
+func parseWeekdays(value string) ([]models.Weekday, bool) {
+	var days []models.Weekday
+	for _, name := range strings.Split(value, ",") {
+		day := models.Weekday(strings.ToUpper(strings.TrimSpace(name)))
+		if weekdayIndex(day) < 0 {
+			return nil, false
+		}
+		days = append(days, day)
+	}
+	return days, true
+}
+
+// weekdayIndex orders weekdays from Monday, returning -1 for unknown names
+func weekdayIndex(day models.Weekday) int {
+	for i, weekday := range models.Weekdays {
+		if weekday == day {
+			return (i + 6) % 7
+		}
+	}
+	return -1
+}
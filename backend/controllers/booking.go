@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var errOccurrenceChanged = errors.New("visit changed concurrently")
+
+// validBooking checks the booking mode of a new hiring and fills in its
+// defaults, writing the error response itself when the hiring is invalid
+func validBooking(c *gin.Context, hiring *models.Hiring, housekeeper models.Housekeeper) bool {
+	if hiring.BookingMode == "" {
+		hiring.BookingMode = models.PlacementBooking
+	}
+
+	switch hiring.BookingMode {
+	case models.PlacementBooking:
+		if hiring.Booking != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only hourly hirings have a booking"})
+			return false
+		}
+		return true
+	case models.HourlyBooking:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "booking_mode must be PLACEMENT or HOURLY"})
+		return false
+	}
+
+	if housekeeper.Category != models.CleaningCategory {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Hourly bookings are only available for cleaning"})
+		return false
+	}
+
+	booking := hiring.Booking
+	if booking == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Hourly hirings need a booking"})
+		return false
+	}
+	if !booking.FirstStart.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The first visit must be in the future"})
+		return false
+	}
+
+	if booking.Recurrence == "" {
+		booking.Recurrence = models.NoRecurrence
+	}
+	if booking.Recurrence == models.NoRecurrence {
+		booking.Occurrences = 1
+	} else if booking.Occurrences < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Recurring bookings need at least 2 occurrences"})
+		return false
+	}
+
+	hiring.StartDate = booking.FirstStart
+	return true
+}
+
+// GetOccurrences godoc
+// @Summary Lists the visits of an hourly hiring
+// @Description This endpoint retrieves the visits of an approved hourly hiring in chronological order
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Success 200 {array} models.Occurrence
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/occurrences [get]
+func GetOccurrences(c *gin.Context) {
+	hiring, _, _, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}})
+	cursor, err := config.DB.Collection("booking_occurrences").Find(context.Background(), bson.M{"hiring_id": hiring.ID}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch visits"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	occurrences := []models.Occurrence{}
+	if err := cursor.All(context.Background(), &occurrences); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode visits"})
+		return
+	}
+
+	c.JSON(http.StatusOK, occurrences)
+}
+
+// SkipOccurrence godoc
+// @Summary Skips a visit
+// @Description This endpoint lets the employer skip a single upcoming visit of a recurring booking; the other visits go on
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param occurrence_id path string true "Visit ID"
+// @Param change body models.OccurrenceChange false "Reason"
+// @Success 200 {object} models.Occurrence
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/occurrences/{occurrence_id}/skip [post]
+func SkipOccurrence(c *gin.Context) {
+	changeOccurrence(c, models.OccurrenceSkipped)
+}
+
+// CancelOccurrence godoc
+// @Summary Cancels a visit
+// @Description This endpoint lets either party cancel a single upcoming visit, giving a reason
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param occurrence_id path string true "Visit ID"
+// @Param change body models.OccurrenceChange true "Reason"
+// @Success 200 {object} models.Occurrence
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/occurrences/{occurrence_id}/cancel [post]
+func CancelOccurrence(c *gin.Context) {
+	changeOccurrence(c, models.OccurrenceCancelled)
+}
+
+// CompleteOccurrence godoc
+// @Summary Confirms a visit took place
+// @Description This endpoint lets the employer confirm a visit once it has ended
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param occurrence_id path string true "Visit ID"
+// @Success 200 {object} models.Occurrence
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/occurrences/{occurrence_id}/complete [post]
+func CompleteOccurrence(c *gin.Context) {
+	changeOccurrence(c, models.OccurrenceCompleted)
+}
+
+func changeOccurrence(c *gin.Context, status models.OccurrenceStatus) {
+	var change models.OccurrenceChange
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&change); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	change.Reason = strings.TrimSpace(change.Reason)
+
+	hiring, _, userType, ok := findPartyHiring(c, false)
+	if !ok {
+		return
+	}
+
+	occurrenceID, err := primitive.ObjectIDFromHex(c.Param("occurrence_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid visit ID"})
+		return
+	}
+
+	var occurrence models.Occurrence
+	err = config.DB.Collection("booking_occurrences").FindOne(context.Background(),
+		bson.M{"_id": occurrenceID, "hiring_id": hiring.ID},
+	).Decode(&occurrence)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Visit not found"})
+		return
+	}
+
+	now := time.Now()
+	switch status {
+	case models.OccurrenceSkipped, models.OccurrenceCompleted:
+		if userType != "employer" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the employer can do this"})
+			return
+		}
+	case models.OccurrenceCancelled:
+		if change.Reason == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A reason is required to cancel a visit"})
+			return
+		}
+	}
+	if occurrence.Status != models.OccurrenceScheduled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only scheduled visits can be changed"})
+		return
+	}
+	if status == models.OccurrenceCompleted {
+		if now.Before(occurrence.EndsAt) {
+			c.JSON(http.StatusConflict, gin.H{"error": "The visit has not ended yet"})
+			return
+		}
+	} else if !now.Before(occurrence.StartsAt) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The visit has already started"})
+		return
+	}
+
+	occurrence.Status = status
+	occurrence.ChangedBy = userType
+	occurrence.Reason = change.Reason
+	occurrence.UpdatedAt = now
+
+	err = events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("booking_occurrences").UpdateOne(uow.Context(),
+			bson.M{"_id": occurrence.ID, "status": models.OccurrenceScheduled},
+			bson.M{"$set": bson.M{
+				"status":     occurrence.Status,
+				"changed_by": occurrence.ChangedBy,
+				"reason":     occurrence.Reason,
+				"updated_at": occurrence.UpdatedAt,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errOccurrenceChanged
+		}
+
+		return uow.Publish(events.OccurrenceChanged{Occurrence: occurrence})
+	})
+	if err != nil {
+		if err == errOccurrenceChanged {
+			c.JSON(http.StatusConflict, gin.H{"error": "The visit was updated in the meantime, please try again"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update visit"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, occurrence)
+}
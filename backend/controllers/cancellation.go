@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var errHiringChanged = errors.New("hiring changed concurrently")
+
+// GetCancellationReasons godoc
+// @Summary Lists cancellation reasons
+// @Description This endpoint lists the reason codes employers and housekeepers can give when cancelling a hiring
+// @Tags hiring
+// @Produce json
+// @Success 200 {object} map[string][]string
+// @Router /hiring/cancellation-reasons [get]
+func GetCancellationReasons(c *gin.Context) {
+	c.JSON(http.StatusOK, models.CancellationReasons)
+}
+
+// CancelHiring godoc
+// @Summary Cancels a hiring
+// @Description This endpoint lets the employer withdraw a pending hiring request, or either party back out of an approved hiring before its start date. Cancelling an approved hiring is counted on the cancelling party's profile, and counted as late within the notice period before the start date.
+// @Tags hiring
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param cancellation body models.CancellationRequest true "Cancellation reason"
+// @Success 200 {object} models.Hiring
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/cancel [post]
+func CancelHiring(c *gin.Context) {
+	var request models.CancellationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiring, userID, userType, ok := findPartyHiring(c, false)
+	if !ok {
+		return
+	}
+
+	if !isCancellationReason(userType, request.Reason) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cancellation reason"})
+		return
+	}
+	if request.Reason == models.OtherReason && strings.TrimSpace(request.Note) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A note is required when the reason is OTHER"})
+		return
+	}
+
+	now := time.Now()
+	switch hiring.Status {
+	case models.Pending:
+		if userType != "employer" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Reject the hiring request instead of cancelling it"})
+			return
+		}
+	case models.Approved:
+		if !hiring.StartDate.IsZero() && !now.Before(hiring.StartDate) {
+			c.JSON(http.StatusConflict, gin.H{"error": "The hiring has already started"})
+			return
+		}
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": "Only pending or approved hirings can be cancelled"})
+		return
+	}
+
+	previousStatus := hiring.Status
+	cancellation := models.Cancellation{
+		CancelledBy: userType,
+		Reason:      request.Reason,
+		Note:        strings.TrimSpace(request.Note),
+		Late:        previousStatus == models.Approved && hiring.StartDate.Sub(now) < cancellationNotice(),
+		CancelledAt: now,
+	}
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		// Matching on the status we checked keeps a concurrent approval or
+		// cancellation from being overwritten
+		result, err := config.DB.Collection("hirings").UpdateOne(uow.Context(),
+			bson.M{"_id": hiring.ID, "status": previousStatus},
+			bson.M{"$set": bson.M{
+				"status":       models.Cancelled,
+				"cancellation": cancellation,
+				"update_at":    now,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errHiringChanged
+		}
+
+		if previousStatus == models.Approved {
+			inc := bson.M{"reliability.cancellation_count": 1}
+			if cancellation.Late {
+				inc["reliability.late_cancellation_count"] = 1
+			}
+			_, err = config.DB.Collection(userType+"s").UpdateOne(uow.Context(), bson.M{"_id": userID}, bson.M{"$inc": inc})
+			if err != nil {
+				return err
+			}
+		}
+
+		hiring.Status = models.Cancelled
+		hiring.Cancellation = &cancellation
+		hiring.UpdatedAt = now
+		return uow.Publish(events.HiringStatusChanged{Hiring: hiring, PreviousStatus: previousStatus})
+	})
+	if err != nil {
+		if err == errHiringChanged {
+			c.JSON(http.StatusConflict, gin.H{"error": "The hiring was updated in the meantime, please try again"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel hiring"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, hiring)
+}
+
+func isCancellationReason(party string, reason models.CancellationReason) bool {
+	for _, allowed := range models.CancellationReasons[party] {
+		if reason == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// cancellationNotice is how long before the start date an approved hiring
+// can be cancelled without it counting as late, configured with
+// HIRING_CANCELLATION_NOTICE_HOURS
+func cancellationNotice() time.Duration {
+	if hours, err := strconv.Atoi(os.Getenv("HIRING_CANCELLATION_NOTICE_HOURS")); err == nil && hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return 48 * time.Hour
+}
@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/contracts"
+	"backend/events"
+	"backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var errContractChanged = errors.New("contract changed concurrently")
+
+// GetContracts godoc
+// @Summary Lists the contract versions of a hiring
+// @Description This endpoint lists every version of the employment contract of a hiring, newest first, with its text, hash and signatures
+// @Tags contracts
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Success 200 {array} models.Contract
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/contracts [get]
+func GetContracts(c *gin.Context) {
+	hiring, _, _, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"document": 0, "signed_document": 0})
+	cursor, err := config.DB.Collection("contracts").Find(context.Background(), bson.M{"hiring_id": hiring.ID}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch contracts"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	list := []models.Contract{}
+	if err := cursor.All(context.Background(), &list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode contracts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GenerateContract godoc
+// @Summary Generates a new contract version
+// @Description This endpoint lets an admin render a new version of the contract of an approved hiring from the current template, for example after fixing a template. Versions not yet signed by both parties are superseded.
+// @Tags contracts
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Success 201 {object} models.Contract
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/contracts [post]
+func GenerateContract(c *gin.Context) {
+	hiring, _, userType, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+	if userType != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can generate contracts"})
+		return
+	}
+	if hiring.Status != models.Approved {
+		c.JSON(http.StatusConflict, gin.H{"error": "Contracts can only be generated for approved hirings"})
+		return
+	}
+
+	contract, err := contracts.Generate(context.Background(), hiring)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A contract was generated in the meantime, please try again"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate contract"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, contract)
+}
+
+// SignContract godoc
+// @Summary Signs a contract
+// @Description This endpoint lets the employer or housekeeper accept a contract version. The time and IP address of the acceptance are recorded; once both parties have signed, the signed PDF is stored.
+// @Tags contracts
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param version path int true "Contract version"
+// @Param acceptance body models.ContractAcceptance true "Acceptance"
+// @Success 200 {object} models.Contract
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/contracts/{version}/sign [post]
+func SignContract(c *gin.Context) {
+	var acceptance models.ContractAcceptance
+	if err := c.ShouldBindJSON(&acceptance); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiring, userID, userType, ok := findPartyHiring(c, false)
+	if !ok {
+		return
+	}
+	contract, ok := findContract(c, hiring)
+	if !ok {
+		return
+	}
+
+	if hiring.Status != models.Approved {
+		c.JSON(http.StatusConflict, gin.H{"error": "The hiring is no longer active"})
+		return
+	}
+	if contract.Status != models.ContractAwaitingSignatures {
+		c.JSON(http.StatusConflict, gin.H{"error": "This contract version cannot be signed anymore"})
+		return
+	}
+	if acceptance.Hash != contract.Hash {
+		c.JSON(http.StatusConflict, gin.H{"error": "The contract you read is not this version; reload it and try again"})
+		return
+	}
+	if _, signed := contracts.Signature(contract, userType); signed {
+		c.JSON(http.StatusConflict, gin.H{"error": "You have already signed this contract"})
+		return
+	}
+
+	var party struct {
+		Name string `bson:"name"`
+	}
+	err := config.DB.Collection(userType+"s").FindOne(context.Background(), bson.M{"_id": userID}).Decode(&party)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load your profile"})
+		return
+	}
+
+	signature := models.ContractSignature{
+		Party:     userType,
+		UserID:    userID,
+		Name:      party.Name,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		SignedAt:  time.Now(),
+	}
+
+	err = events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		collection := config.DB.Collection("contracts")
+		// Matching on the status and the missing signature keeps a double
+		// click or a superseding version from getting through
+		err := collection.FindOneAndUpdate(uow.Context(),
+			bson.M{
+				"_id":              contract.ID,
+				"status":           models.ContractAwaitingSignatures,
+				"signatures.party": bson.M{"$ne": userType},
+			},
+			bson.M{"$push": bson.M{"signatures": signature}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&contract)
+		if err == mongo.ErrNoDocuments {
+			return errContractChanged
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(contract.Signatures) == 2 {
+			contract.Status = models.ContractSigned
+			contract.SignedAt = signature.SignedAt
+			contract.SignedDocument = contracts.Document(contract)
+			_, err = collection.UpdateOne(uow.Context(), bson.M{"_id": contract.ID}, bson.M{"$set": bson.M{
+				"status":          contract.Status,
+				"signed_at":       contract.SignedAt,
+				"signed_document": contract.SignedDocument,
+			}})
+			if err != nil {
+				return err
+			}
+		}
+
+		return uow.Publish(events.ContractSigned{Contract: contracts.WithoutDocuments(contract), Party: userType})
+	})
+	if err != nil {
+		if err == errContractChanged {
+			c.JSON(http.StatusConflict, gin.H{"error": "The contract was updated in the meantime, please reload it"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign contract"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts.WithoutDocuments(contract))
+}
+
+// DownloadContract godoc
+// @Summary Downloads a contract as PDF
+// @Description This endpoint downloads a contract version as PDF, with both signatures once it is signed
+// @Tags contracts
+// @Produce application/pdf
+// @Param id path string true "Hiring ID"
+// @Param version path int true "Contract version"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /hiring/{id}/contracts/{version}/pdf [get]
+func DownloadContract(c *gin.Context) {
+	hiring, _, _, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+	contract, ok := findContract(c, hiring)
+	if !ok {
+		return
+	}
+
+	document := contract.Document
+	if len(contract.SignedDocument) > 0 {
+		document = contract.SignedDocument
+	}
+
+	filename := fmt.Sprintf("contract-%s-v%d.pdf", hiring.ID.Hex(), contract.Version)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", document)
+}
+
+// CreateContractTemplate godoc
+// @Summary Adds a contract template
+// @Description This endpoint adds a contract template, written as a Go text/template over .Hiring, .Employer, .Housekeeper, .Version and .Date with the date and money functions. Lines starting with "# " become headings. New contracts use the newest template for their employment type.
+// @Tags contracts
+// @Accept json
+// @Produce json
+// @Param template body models.ContractTemplate true "Template"
+// @Success 201 {object} models.ContractTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/contract-templates [post]
+func CreateContractTemplate(c *gin.Context) {
+	var tmpl models.ContractTemplate
+	if err := c.ShouldBindJSON(&tmpl); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := contracts.Validate(tmpl.Body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template: " + err.Error()})
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	tmpl.ID = primitive.NilObjectID
+	tmpl.CreatedBy = userID
+	tmpl.CreatedAt = time.Now()
+
+	result, err := config.DB.Collection("contract_templates").InsertOne(context.Background(), tmpl)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save template"})
+		return
+	}
+	tmpl.ID = result.InsertedID.(primitive.ObjectID)
+
+	c.JSON(http.StatusCreated, tmpl)
+}
+
+// GetContractTemplates godoc
+// @Summary Lists contract templates
+// @Description This endpoint lists the contract templates, newest first
+// @Tags contracts
+// @Produce json
+// @Success 200 {array} models.ContractTemplate
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/contract-templates [get]
+func GetContractTemplates(c *gin.Context) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := config.DB.Collection("contract_templates").Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch templates"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	templates := []models.ContractTemplate{}
+	if err := cursor.All(context.Background(), &templates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func findContract(c *gin.Context, hiring models.Hiring) (models.Contract, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contract version"})
+		return models.Contract{}, false
+	}
+
+	var contract models.Contract
+	err = config.DB.Collection("contracts").FindOne(context.Background(),
+		bson.M{"hiring_id": hiring.ID, "version": version},
+	).Decode(&contract)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contract not found"})
+		return models.Contract{}, false
+	}
+	return contract, true
+}
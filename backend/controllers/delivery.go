@@ -0,0 +1,386 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/deliveries"
+	"backend/events"
+	"backend/models"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var errDeliveryChanged = errors.New("delivery changed concurrently")
+
+// PlanDelivery godoc
+// @Summary Plans the delivery of a housekeeper
+// @Description This endpoint lets staff schedule the handover of the housekeeper of an approved placement, with the pickup and destination address and the staff member in charge
+// @Tags deliveries
+// @Accept json
+// @Produce json
+// @Param delivery body models.DeliveryPlan true "Delivery plan"
+// @Success 201 {object} models.Delivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/deliveries [post]
+func PlanDelivery(c *gin.Context) {
+	var plan models.DeliveryPlan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var hiring models.Hiring
+	err := config.DB.Collection("hirings").FindOne(context.Background(), bson.M{"_id": plan.HiringID}).Decode(&hiring)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Hiring not found"})
+		return
+	}
+	if hiring.Status != models.Approved || hiring.BookingMode == models.HourlyBooking {
+		c.JSON(http.StatusConflict, gin.H{"error": "Deliveries can only be planned for approved placements"})
+		return
+	}
+
+	now := time.Now()
+	delivery := models.Delivery{
+		HiringID:      hiring.ID,
+		EmployerID:    hiring.EmployerID,
+		HousekeeperID: hiring.HousekeeperID,
+		Type:          hiring.DeliveryType,
+		Status:        models.DeliveryScheduled,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if delivery.Type == "" {
+		delivery.Type = models.HomeDelivery
+	}
+	if !applyDeliverySchedule(c, &delivery, plan.DeliverySchedule) {
+		return
+	}
+
+	err = events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("deliveries").InsertOne(uow.Context(), delivery)
+		if err != nil {
+			return err
+		}
+		delivery.ID = result.InsertedID.(primitive.ObjectID)
+
+		return uow.Publish(events.DeliveryChanged{Delivery: delivery})
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A delivery is already planned for this hiring; reschedule it instead"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to plan delivery"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, delivery)
+}
+
+// GetDeliveries godoc
+// @Summary Lists deliveries
+// @Description This endpoint lists deliveries by scheduled time, optionally filtered by status, staff member and a from/to date range (YYYY-MM-DD, inclusive)
+// @Tags deliveries
+// @Produce json
+// @Param status query string false "Delivery status"
+// @Param staff_id query string false "Staff member ID"
+// @Param from query string false "First day"
+// @Param to query string false "Last day"
+// @Success 200 {array} models.Delivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/deliveries [get]
+func GetDeliveries(c *gin.Context) {
+	filter := bson.M{}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if value := c.Query("staff_id"); value != "" {
+		staffID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff ID"})
+			return
+		}
+		filter["staff_id"] = staffID
+	}
+
+	scheduled := bson.M{}
+	if value := c.Query("from"); value != "" {
+		from, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date in YYYY-MM-DD format"})
+			return
+		}
+		scheduled["$gte"] = from
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format"})
+			return
+		}
+		scheduled["$lt"] = to.AddDate(0, 0, 1)
+	}
+	if len(scheduled) > 0 {
+		filter["scheduled_at"] = scheduled
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "scheduled_at", Value: 1}}).SetLimit(200)
+	cursor, err := config.DB.Collection("deliveries").Find(context.Background(), filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	list := []models.Delivery{}
+	if err := cursor.All(context.Background(), &list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// RescheduleDelivery godoc
+// @Summary Reschedules a delivery
+// @Description This endpoint changes the time, addresses or staff member of a delivery that has not left yet
+// @Tags deliveries
+// @Accept json
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Param schedule body models.DeliverySchedule true "New schedule"
+// @Success 200 {object} models.Delivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/deliveries/{id} [put]
+func RescheduleDelivery(c *gin.Context) {
+	var schedule models.DeliverySchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	delivery, ok := findDelivery(c)
+	if !ok {
+		return
+	}
+	if delivery.Status != models.DeliveryScheduled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only deliveries that have not left can be rescheduled"})
+		return
+	}
+	if !applyDeliverySchedule(c, &delivery, schedule) {
+		return
+	}
+	delivery.UpdatedAt = time.Now()
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("deliveries").UpdateOne(uow.Context(),
+			bson.M{"_id": delivery.ID, "status": models.DeliveryScheduled},
+			bson.M{"$set": bson.M{
+				"scheduled_at":        delivery.ScheduledAt,
+				"pickup_address":      delivery.PickupAddress,
+				"destination_address": delivery.DestinationAddress,
+				"staff_id":            delivery.StaffID,
+				"staff_name":          delivery.StaffName,
+				"notes":               delivery.Notes,
+				"updated_at":          delivery.UpdatedAt,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errDeliveryChanged
+		}
+
+		return uow.Publish(events.DeliveryChanged{Delivery: delivery, PreviousStatus: models.DeliveryScheduled})
+	})
+	if err != nil {
+		deliveryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// UpdateDeliveryStatus godoc
+// @Summary Updates the status of a delivery
+// @Description This endpoint moves a delivery forward, from SCHEDULED to EN_ROUTE when the housekeeper leaves and from EN_ROUTE to DELIVERED on handover
+// @Tags deliveries
+// @Accept json
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Param status body models.DeliveryStatusUpdate true "New status"
+// @Success 200 {object} models.Delivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/deliveries/{id}/status [post]
+func UpdateDeliveryStatus(c *gin.Context) {
+	var update models.DeliveryStatusUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	delivery, ok := findDelivery(c)
+	if !ok {
+		return
+	}
+	if deliveries.Next(delivery.Status) != update.Status {
+		c.JSON(http.StatusConflict, gin.H{"error": "A " + string(delivery.Status) + " delivery cannot move to " + string(update.Status)})
+		return
+	}
+
+	previousStatus := delivery.Status
+	now := time.Now()
+	delivery.Status = update.Status
+	delivery.UpdatedAt = now
+	set := bson.M{"status": delivery.Status, "updated_at": now}
+	if delivery.Status == models.DeliveryEnRoute {
+		delivery.DepartedAt = now
+		set["departed_at"] = now
+	} else {
+		delivery.DeliveredAt = now
+		set["delivered_at"] = now
+	}
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("deliveries").UpdateOne(uow.Context(),
+			bson.M{"_id": delivery.ID, "status": previousStatus},
+			bson.M{"$set": set},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errDeliveryChanged
+		}
+
+		return uow.Publish(events.DeliveryChanged{Delivery: delivery, PreviousStatus: previousStatus})
+	})
+	if err != nil {
+		deliveryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// GetHiringDelivery godoc
+// @Summary Fetches the delivery of a hiring
+// @Description This endpoint retrieves the planned delivery of a placement for its employer, housekeeper or an admin
+// @Tags hiring
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Success 200 {object} models.Delivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /hiring/{id}/delivery [get]
+func GetHiringDelivery(c *gin.Context) {
+	hiring, _, _, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+
+	var delivery models.Delivery
+	err := config.DB.Collection("deliveries").FindOne(context.Background(), bson.M{"hiring_id": hiring.ID}).Decode(&delivery)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No delivery has been planned yet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func findDelivery(c *gin.Context) (models.Delivery, bool) {
+	deliveryID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return models.Delivery{}, false
+	}
+
+	var delivery models.Delivery
+	err = config.DB.Collection("deliveries").FindOne(context.Background(), bson.M{"_id": deliveryID}).Decode(&delivery)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return models.Delivery{}, false
+	}
+	return delivery, true
+}
+
+// applyDeliverySchedule validates schedule and copies it onto delivery,
+// filling in the default addresses and the staff member's name: the office
+// for pickup, and the employer's address as destination unless the employer
+// picks the housekeeper up at the office. It writes
+// the error response itself when it fails.
+func applyDeliverySchedule(c *gin.Context, delivery *models.Delivery, schedule models.DeliverySchedule) bool {
+	if !schedule.ScheduledAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The delivery must be scheduled in the future"})
+		return false
+	}
+
+	var staff models.Admin
+	err := config.DB.Collection("admins").FindOne(context.Background(), bson.M{"_id": schedule.StaffID}).Decode(&staff)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Staff member not found"})
+		return false
+	}
+
+	pickup := strings.TrimSpace(schedule.PickupAddress)
+	if pickup == "" {
+		pickup = deliveries.OfficeAddress()
+	}
+	destination := strings.TrimSpace(schedule.DestinationAddress)
+	// The employer collects the housekeeper at the office for a pickup
+	if destination == "" && delivery.Type == models.OfficePickup {
+		destination = deliveries.OfficeAddress()
+	}
+	if destination == "" {
+		var employer models.Employer
+		err := config.DB.Collection("employers").FindOne(context.Background(), bson.M{"_id": delivery.EmployerID}).Decode(&employer)
+		if err != nil || employer.Address == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The employer has no address; give a destination address"})
+			return false
+		}
+		destination = employer.Address
+	}
+
+	delivery.ScheduledAt = schedule.ScheduledAt
+	delivery.PickupAddress = pickup
+	delivery.DestinationAddress = destination
+	delivery.StaffID = staff.ID
+	delivery.StaffName = staff.Name
+	delivery.Notes = strings.TrimSpace(schedule.Notes)
+	return true
+}
+
+func deliveryError(c *gin.Context, err error) {
+	if err == errDeliveryChanged {
+		c.JSON(http.StatusConflict, gin.H{"error": "The delivery was updated in the meantime, please try again"})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update delivery"})
+	}
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RegisterDevice godoc
+// @Summary Registers a push notification token for a device
+// @Description This endpoint registers or refreshes the Expo push token of the authenticated user's device
+// @Tags device
+// @Accept json
+// @Produce json
+// @Param device body models.DeviceToken true "Device token data"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /devices [post]
+func RegisterDevice(c *gin.Context) {
+	userID, userType, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return
+	}
+
+	var device models.DeviceToken
+	if err := c.ShouldBindJSON(&device); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// A token belongs to a single device, so drop it from any other user
+	// that registered it before (e.g. after logging out on a shared phone)
+	_, err := config.DB.Collection("device_tokens").DeleteMany(context.Background(), bson.M{
+		"token":   device.Token,
+		"user_id": bson.M{"$ne": userID},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register device"})
+		return
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"user_type":  userType,
+			"token":      device.Token,
+			"platform":   device.Platform,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+
+	_, err = config.DB.Collection("device_tokens").UpdateOne(
+		context.Background(),
+		bson.M{"user_id": userID, "device_id": device.DeviceID},
+		update,
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register device"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device registered successfully"})
+}
+
+// GetDevices godoc
+// @Summary Lists the devices registered by the authenticated user
+// @Description This endpoint retrieves the push notification devices of the authenticated user
+// @Tags device
+// @Produce json
+// @Success 200 {array} models.DeviceToken
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /devices [get]
+func GetDevices(c *gin.Context) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return
+	}
+
+	cursor, err := config.DB.Collection("device_tokens").Find(context.Background(), bson.M{"user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch devices"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	devices := []models.DeviceToken{}
+	if err := cursor.All(context.Background(), &devices); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode devices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+// UnregisterDevice godoc
+// @Summary Removes a device's push notification token
+// @Description This endpoint stops push notifications to one of the authenticated user's devices
+// @Tags device
+// @Produce json
+// @Param device_id path string true "Device ID"
+// @Success 200 {object} models.GenericResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /devices/{device_id} [delete]
+func UnregisterDevice(c *gin.Context) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return
+	}
+
+	result, err := config.DB.Collection("device_tokens").DeleteOne(context.Background(), bson.M{
+		"user_id":   userID,
+		"device_id": c.Param("device_id"),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unregister device"})
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device unregistered successfully"})
+}
@@ -1,19 +1,21 @@
 package controllers
 
 import (
+	"backend/availability"
+	"backend/bookings"
 	"backend/config"
+	"backend/events"
 	"backend/models"
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
-	"net/smtp"
-	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // CreateHiring godoc
@@ -25,6 +27,7 @@ import (
 // @Param hiring body models.Hiring true "Hiring request data"
 // @Success 201 {object} models.GenericResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /create-hiring [post]
 func CreateHiring(c *gin.Context) {
@@ -35,6 +38,9 @@ func CreateHiring(c *gin.Context) {
 	}
 
 	hiring.Status = models.Pending
+	hiring.Trial = nil
+	hiring.ReplacementFor = primitive.NilObjectID
+	hiring.EndedAt = time.Time{}
 	hiring.CreatedAt = time.Now()
 	hiring.UpdatedAt = time.Now()
 
@@ -43,7 +49,6 @@ func CreateHiring(c *gin.Context) {
 
 	// hiring.EmployerID, _ = primitive.ObjectIDFromHex(hiring.EmployerID.Hex())
 
-	fmt.Println(hiring.EmployerID)
 	err := config.DB.Collection("employers").FindOne(context.Background(), bson.M{"_id": hiring.EmployerID}).Decode(&employer)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Employer not found"})
@@ -56,56 +61,62 @@ func CreateHiring(c *gin.Context) {
 		return
 	}
 
-	result, err := config.DB.Collection("hirings").InsertOne(context.Background(), hiring)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create hiring request"})
+	if !validBooking(c, &hiring, housekeeper) {
 		return
 	}
+	if hiring.DeliveryType == "" && hiring.BookingMode != models.HourlyBooking {
+		hiring.DeliveryType = models.HomeDelivery
+	}
 
-	go sendHiringEmail(employer, housekeeper, hiring)
+	if hiring.BookingMode == models.HourlyBooking {
+		err := bookings.Check(context.Background(), hiring)
+		if errors.Is(err, bookings.ErrUnavailable) {
+			c.JSON(http.StatusConflict, gin.H{"error": "One of the visits falls outside the housekeeper's availability"})
+			return
+		}
+		if errors.Is(err, bookings.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "One of the visits clashes with other work of the housekeeper"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check availability"})
+			return
+		}
+	} else {
+		if !hiring.EndDate.IsZero() && !hiring.EndDate.After(hiring.StartDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The end date must be after the start date"})
+			return
+		}
+		conflicts, err := availability.Conflicts(context.Background(), hiring)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check availability"})
+			return
+		}
+		if conflicts {
+			c.JSON(http.StatusConflict, gin.H{"error": "The housekeeper already has a placement or visits on these dates"})
+			return
+		}
+	}
 
-	c.JSON(http.StatusCreated, gin.H{"id": result.InsertedID})
-}
+	// Only contact details are needed downstream; keep password hashes out of the outbox
+	employer.Password = ""
+	housekeeper.Password = ""
+
+	err = events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("hirings").InsertOne(uow.Context(), hiring)
+		if err != nil {
+			return err
+		}
 
-func sendHiringEmail(employer models.Employer, housekeeper models.Housekeeper, hiring models.Hiring) {
-	from := os.Getenv("SMTP_USERNAME")
-	password := os.Getenv("SMTP_PASSWORD")
-	to := os.Getenv("ADMIN_EMAIL")
-	smtpHost := os.Getenv("SMTP_HOST")
-	smtpPort := os.Getenv("SMTP_PORT")
-
-	message := fmt.Sprintf(`
-		Subject: New Hiring Request
-
-		Employer Details:
-		Name: %s
-		Email: %s
-		Phone: %s
-		Address: %s
-		Family Size: %d
-
-		Housekeeper Details:
-		Name: %s
-		Category: %s
-		Employment Type: %s
-		Experience: %d years
-
-		Hiring Details:
-		Salary offered: $%.2f
-		Start Date: %s
-		Delivery Type: %s
-		Requirements: %s
-	`,
-		employer.Name, employer.Email, employer.PhoneNumber, employer.Address, employer.FamilySize,
-		housekeeper.Name, housekeeper.Category, housekeeper.EmploymentType, housekeeper.Experience,
-		hiring.SalaryOffer, hiring.StartDate.Format("2006-01-02"), hiring.DeliveryType, hiring.Requirements)
-
-	auth := smtp.PlainAuth("", from, password, smtpHost)
-	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, from, []string{to}, []byte(message))
+		hiring.ID = result.InsertedID.(primitive.ObjectID)
+		return uow.Publish(events.HiringCreated{Hiring: hiring, Employer: employer, Housekeeper: housekeeper})
+	})
 	if err != nil {
-		fmt.Println("Error sending email: ", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create hiring request"})
+		return
 	}
 
+	c.JSON(http.StatusCreated, gin.H{"id": hiring.ID})
 }
 
 // GetHiringStatus godoc
@@ -136,8 +147,73 @@ func GetHiringStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, hiring)
 }
 
+// findPartyHiring loads the hiring in the id path parameter and checks that
+// the authenticated user is its employer or housekeeper, or an admin when
+// allowAdmin is set. It writes the error response itself when it fails.
+func findPartyHiring(c *gin.Context, allowAdmin bool) (models.Hiring, primitive.ObjectID, string, bool) {
+	userID, userType, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return models.Hiring{}, primitive.NilObjectID, "", false
+	}
+
+	hiringID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hiring ID"})
+		return models.Hiring{}, primitive.NilObjectID, "", false
+	}
+
+	var hiring models.Hiring
+	err = config.DB.Collection("hirings").FindOne(context.Background(), bson.M{"_id": hiringID}).Decode(&hiring)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Hiring not found"})
+		return models.Hiring{}, primitive.NilObjectID, "", false
+	}
+
+	if !isHiringParty(hiring, userID, userType) && !(allowAdmin && userType == "admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the employer and housekeeper of this hiring have access"})
+		return models.Hiring{}, primitive.NilObjectID, "", false
+	}
+
+	return hiring, userID, userType, true
+}
+
+// isHiringParty reports whether the authenticated user is the employer or
+// housekeeper of hiring
+func isHiringParty(hiring models.Hiring, userID primitive.ObjectID, userType string) bool {
+	switch userType {
+	case "employer":
+		return hiring.EmployerID == userID
+	case "housekeeper":
+		return hiring.HousekeeperID == userID
+	default:
+		return false
+	}
+}
+
+var (
+	errNotActive        = errors.New("hiring is not active")
+	errStatusTransition = errors.New("hiring cannot move to that status")
+)
+
+// hiringTransitions lists the statuses UpdateHiringStatus may move a hiring
+// to from each status; cancellations go through CancelHiring instead, and
+// only the expiry job expires requests
+var hiringTransitions = map[models.HiringStatus][]models.HiringStatus{
+	models.Pending:  {models.Approved, models.Rejected},
+	models.Approved: {models.Completed, models.Terminated},
+}
+
+func canTransition(from, to models.HiringStatus) bool {
+	for _, allowed := range hiringTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
 func UpdateHiringStatus(c *gin.Context) {
-	hiringID := c.Param("id")
 	var statusUpdate struct {
 		Status string `json:"status"`
 	}
@@ -146,42 +222,120 @@ func UpdateHiringStatus(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
+	status := models.HiringStatus(statusUpdate.Status)
 
-	// Find the hiring request by ID
-	collection := config.DB.Collection("hirings") // Ensure the correct collection name
-	hiringObjectID, err := primitive.ObjectIDFromHex(hiringID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Hiring ID"})
+	// Cancellations need a reason and update reliability counts
+	if status == models.Cancelled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use the cancel endpoint to cancel a hiring"})
+		return
+	}
+	switch status {
+	case models.Approved, models.Rejected, models.Completed, models.Terminated:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of APPROVED, REJECTED, COMPLETED or TERMINATED"})
 		return
 	}
 
-	// Update statement using the correct BSON structure with keyed fields
-	update := bson.D{
-		{Key: "$set", Value: bson.D{
-			{Key: "status", Value: statusUpdate.Status},
-			{Key: "updated_at", Value: time.Now()},
-		}},
+	found, _, userType, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+	// The housekeeper answers the request; the employer cannot do it for them
+	if (status == models.Approved || status == models.Rejected) && userType != "housekeeper" && userType != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the housekeeper or an admin can approve or reject a hiring"})
+		return
+	}
+	// Employers end a placement by cancelling it or asking for a replacement,
+	// which record a reason and keep the trial guarantee
+	if status == models.Terminated && userType == "employer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Use the cancel or replacement endpoint to end a placement"})
+		return
 	}
 
-	result, err := collection.UpdateOne(
-		c,
-		bson.D{{Key: "_id", Value: hiringObjectID}},
-		update,
-	)
+	collection := config.DB.Collection("hirings")
+	hiringObjectID := found.ID
+
+	var hiring models.Hiring
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		err := collection.FindOne(uow.Context(), bson.D{{Key: "_id", Value: hiringObjectID}}).Decode(&hiring)
+		if err != nil {
+			return err
+		}
+		previousStatus := hiring.Status
+		if status == models.Terminated && previousStatus != models.Approved {
+			return errNotActive
+		}
+		if !canTransition(previousStatus, status) {
+			return errStatusTransition
+		}
+
+		set := bson.M{"status": status, "updated_at": time.Now()}
+		if status == models.Completed || status == models.Terminated {
+			set["ended_at"] = time.Now()
+		}
+		// An approved hiring takes on the terms both parties agreed on
+		if status == models.Approved {
+			if err := openInterviewCheck(uow.Context(), hiring.ID); err != nil {
+				return err
+			}
+			terms, err := acceptedTerms(uow.Context(), hiring)
+			if err != nil {
+				return err
+			}
+			for field, value := range terms {
+				set[field] = value
+			}
+
+			// Hourly bookings are paid per visit and have no trial period
+			if hiring.BookingMode != models.HourlyBooking {
+				startDate := hiring.StartDate
+				if termsStart, ok := terms["start_date"].(time.Time); ok && !termsStart.IsZero() {
+					startDate = termsStart
+				}
+				set["trial"] = newTrial(startDate, time.Now())
+			}
+		}
+
+		// Filtering on the status read above keeps two concurrent updates
+		// from both applying their side effects
+		err = collection.FindOneAndUpdate(
+			uow.Context(),
+			bson.D{{Key: "_id", Value: hiringObjectID}, {Key: "status", Value: previousStatus}},
+			bson.M{"$set": set},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&hiring)
+		if err == mongo.ErrNoDocuments {
+			return errStatusTransition
+		}
+		if err != nil {
+			return err
+		}
+
+		return uow.Publish(events.HiringStatusChanged{Hiring: hiring, PreviousStatus: previousStatus})
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Hiring not found"})
+		} else if errors.Is(err, bookings.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "One of the visits clashes with other work of the housekeeper"})
+		} else if errors.Is(err, bookings.ErrUnavailable) {
+			c.JSON(http.StatusConflict, gin.H{"error": "One of the visits falls outside the housekeeper's availability"})
+		} else if errors.Is(err, availability.ErrBooked) {
+			c.JSON(http.StatusConflict, gin.H{"error": "The housekeeper already has a placement or visits on these dates"})
+		} else if err == errNotActive {
+			c.JSON(http.StatusConflict, gin.H{"error": "Only approved hirings can be terminated"})
+		} else if err == errStatusTransition {
+			c.JSON(http.StatusConflict, gin.H{"error": "The hiring is " + string(hiring.Status) + " and cannot be moved to " + string(status)})
+		} else if err == errInterviewOpen {
+			c.JSON(http.StatusConflict, gin.H{"error": "An interview has not taken place yet; record its outcome or cancel it first"})
+		} else if err == errOfferOpen {
+			c.JSON(http.StatusConflict, gin.H{"error": "An offer is waiting for an answer; accept or decline it first"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update hiring status"})
 		}
 		return
 	}
 
-	if result.MatchedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Hiring not found"})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{"message": "Hiring status updated successfully"})
 }
 
@@ -1,11 +1,15 @@
 package controllers
 
 import (
+	"backend/availability"
 	"backend/config"
+	"backend/events"
 	"backend/models"
+	"backend/search"
 	"context"
 	"log" // Import the log package
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,14 +20,18 @@ import (
 
 // GetHousekeepers godoc
 // @Summary Retrieves a list of housekeepers based on filter criteria
-// @Description This endpoint fetches housekeepers based on category, employment type, and location filters
+// @Description This endpoint fetches housekeepers based on category, employment type, location, keyword and availability filters, best ranked first. Housekeepers with a placement on the date asked for are left out, or with a current placement when no date is given.
 // @Tags housekeeper
 // @Accept json
 // @Produce json
 // @Param category query string false "Category (NORMAL, CHILD_CARE, CLEANING)"
 // @Param employment_type query string false "Employment Type (LIVE_OUT, LIVE_IN)"
 // @Param location query string false "Location"
+// @Param q query string false "Keywords matched against name, location, skills and certifications"
+// @Param available_on query string false "Only housekeepers free on this date (YYYY-MM-DD)"
+// @Param days query string false "Only housekeepers free on all these weekdays, comma separated (MONDAY,TUESDAY)"
 // @Success 200 {array} models.Housekeeper
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /housekeepers [get]
 func GetHousekeepers(c *gin.Context) {
@@ -38,10 +46,49 @@ func GetHousekeepers(c *gin.Context) {
 	if location := c.Query("location"); location != "" {
 		filter["location"] = location
 	}
+	if keywords := search.Tokenize(c.Query("q")); len(keywords) > 0 {
+		filter["search_keywords"] = bson.M{"$all": keywords}
+	}
 
-	filter["is_available"] = true
+	var conditions bson.A
+	// Without a date, the placements asked about are the current ones
+	placedOn := time.Now().UTC().Truncate(24 * time.Hour)
+	if date := c.Query("available_on"); date != "" {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "available_on must be a date written as YYYY-MM-DD"})
+			return
+		}
+		conditions = append(conditions, availability.OnDate(day))
+		placedOn = day
+	}
+	if value := c.Query("days"); value != "" {
+		days, ok := parseWeekdays(value)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a comma separated list of weekdays such as MONDAY,TUESDAY"})
+			return
+		}
+		conditions = append(conditions, availability.OnDays(days))
+	}
+	// The calendar replaces the availability flag when dates are asked for;
+	// the flag only says whether the housekeeper has a placement right now.
+	// The calendar does not know about placements, so housekeepers placed on
+	// the day asked for are left out separately.
+	if len(conditions) > 0 {
+		placed, err := availability.PlacedDuring(context.Background(), placedOn, placedOn.AddDate(0, 0, 1))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while fetching housekeepers"})
+			return
+		}
+		if len(placed) > 0 {
+			conditions = append(conditions, bson.M{"_id": bson.M{"$nin": placed}})
+		}
+		filter["$and"] = conditions
+	} else {
+		filter["is_available"] = true
+	}
 
-	opts := options.Find().SetSort(bson.D{{Key: "rating", Value: -1}})
+	opts := options.Find().SetSort(bson.D{{Key: "ranking_score", Value: -1}, {Key: "rating", Value: -1}})
 
 	cursor, err := config.DB.Collection("housekeepers").Find(context.Background(), filter, opts)
 	if err != nil {
@@ -162,22 +209,31 @@ func UpdateHousekeeper(c *gin.Context) {
 		updateDoc = append(updateDoc, bson.E{Key: "$set", Value: setDoc})
 	}
 
-	result, err := config.DB.Collection("housekeepers").UpdateOne(
-		context.Background(),
-		bson.M{"_id": id},
-		updateDoc,
-	)
+	err = events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		var housekeeper models.Housekeeper
+		err := config.DB.Collection("housekeepers").FindOneAndUpdate(
+			uow.Context(),
+			bson.M{"_id": id},
+			updateDoc,
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&housekeeper)
+		if err != nil {
+			return err
+		}
+
+		housekeeper.Password = ""
+		return uow.Publish(events.HousekeeperUpdated{Housekeeper: housekeeper})
+	})
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Housekeeper not found"})
+			return
+		}
 		log.Printf("Error updating housekeeper: %v", err) // Log the error
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while updating housekeeper"})
 		return
 	}
 
-	if result.MatchedCount == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Housekeeper not found"})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{"message": "Housekeeper updated successfully"})
 }
 
@@ -0,0 +1,616 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultInterviewMinutes = 30
+
+var (
+	errInterviewConflict = errors.New("housekeeper has another interview at that time")
+	errInterviewChanged  = errors.New("interview changed concurrently")
+	errInterviewOpen     = errors.New("an interview has not taken place yet")
+)
+
+// ProposeInterview godoc
+// @Summary Proposes an interview
+// @Description This endpoint lets the employer of a pending hiring propose up to five interview slots, in person or by phone. The housekeeper then confirms one of them or proposes others.
+// @Tags interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param proposal body models.InterviewProposal true "Interview proposal"
+// @Success 201 {object} models.Interview
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/interviews [post]
+func ProposeInterview(c *gin.Context) {
+	var proposal models.InterviewProposal
+	if err := c.ShouldBindJSON(&proposal); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiring, _, userType, ok := findPartyHiring(c, false)
+	if !ok {
+		return
+	}
+	if userType != "employer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the employer can request an interview"})
+		return
+	}
+	if hiring.Status != models.Pending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Interviews can only be scheduled while the hiring is pending"})
+		return
+	}
+
+	now := time.Now()
+	interview := models.Interview{
+		HiringID:        hiring.ID,
+		EmployerID:      hiring.EmployerID,
+		HousekeeperID:   hiring.HousekeeperID,
+		Mode:            proposal.Mode,
+		Location:        strings.TrimSpace(proposal.Location),
+		DurationMinutes: proposal.DurationMinutes,
+		ProposedBy:      userType,
+		Status:          models.InterviewProposed,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if interview.DurationMinutes == 0 {
+		interview.DurationMinutes = defaultInterviewMinutes
+	}
+	if !validInterviewSetup(c, interview) {
+		return
+	}
+
+	slots, ok := validInterviewSlots(c, proposal.Slots)
+	if !ok {
+		return
+	}
+	interview.Slots = slots
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		if err := openInterviewCheck(uow.Context(), hiring.ID); err != nil {
+			return err
+		}
+		if err := checkInterviewSlots(uow.Context(), interview); err != nil {
+			return err
+		}
+
+		result, err := config.DB.Collection("interviews").InsertOne(uow.Context(), interview)
+		if err != nil {
+			return err
+		}
+		interview.ID = result.InsertedID.(primitive.ObjectID)
+
+		return uow.Publish(events.InterviewChanged{Interview: interview, Actor: userType})
+	})
+	if err != nil {
+		interviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, interview)
+}
+
+// GetInterviews godoc
+// @Summary Lists the interviews of a hiring
+// @Description This endpoint retrieves the interviews of a hiring, newest first
+// @Tags interviews
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Success 200 {array} models.Interview
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/interviews [get]
+func GetInterviews(c *gin.Context) {
+	hiring, _, _, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := config.DB.Collection("interviews").Find(context.Background(), bson.M{"hiring_id": hiring.ID}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch interviews"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	interviews := []models.Interview{}
+	if err := cursor.All(context.Background(), &interviews); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode interviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, interviews)
+}
+
+// ConfirmInterview godoc
+// @Summary Confirms an interview slot
+// @Description This endpoint lets the party that did not propose the slots pick one of them. It fails when the housekeeper has another confirmed interview at that time.
+// @Tags interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param interview_id path string true "Interview ID"
+// @Param slot body models.InterviewSlotChoice true "Chosen slot"
+// @Success 200 {object} models.Interview
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/interviews/{interview_id}/confirm [post]
+func ConfirmInterview(c *gin.Context) {
+	var choice models.InterviewSlotChoice
+	if err := c.ShouldBindJSON(&choice); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, interview, userType, ok := findInterview(c)
+	if !ok {
+		return
+	}
+	if interview.Status != models.InterviewProposed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only proposed interviews can be confirmed"})
+		return
+	}
+	if interview.ProposedBy == userType {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot confirm your own proposal"})
+		return
+	}
+
+	proposed := false
+	for _, slot := range interview.Slots {
+		if slot.Equal(choice.Slot) {
+			proposed = true
+		}
+	}
+	if !proposed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The slot is not one of the proposed slots"})
+		return
+	}
+	if !choice.Slot.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The slot has already passed"})
+		return
+	}
+
+	previousStatus := interview.Status
+	interview.Status = models.InterviewConfirmed
+	interview.ScheduledAt = choice.Slot
+	interview.EndsAt = choice.Slot.Add(time.Duration(interview.DurationMinutes) * time.Minute)
+	interview.UpdatedAt = time.Now()
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		if err := lockHousekeeperInterviews(uow.Context(), interview.HousekeeperID); err != nil {
+			return err
+		}
+		if err := checkInterviewConflict(uow.Context(), interview, interview.ScheduledAt); err != nil {
+			return err
+		}
+
+		result, err := config.DB.Collection("interviews").UpdateOne(uow.Context(),
+			bson.M{"_id": interview.ID, "status": previousStatus, "proposed_by": interview.ProposedBy},
+			bson.M{"$set": bson.M{
+				"status":       interview.Status,
+				"scheduled_at": interview.ScheduledAt,
+				"ends_at":      interview.EndsAt,
+				"updated_at":   interview.UpdatedAt,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errInterviewChanged
+		}
+
+		return uow.Publish(events.InterviewChanged{Interview: interview, PreviousStatus: previousStatus, Actor: userType})
+	})
+	if err != nil {
+		interviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, interview)
+}
+
+// RescheduleInterview godoc
+// @Summary Proposes new interview slots
+// @Description This endpoint lets either party replace the slots of a proposed or confirmed interview with new ones, which the other party then confirms
+// @Tags interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param interview_id path string true "Interview ID"
+// @Param proposal body models.InterviewProposal true "New slots"
+// @Success 200 {object} models.Interview
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/interviews/{interview_id}/reschedule [post]
+func RescheduleInterview(c *gin.Context) {
+	var proposal models.InterviewProposal
+	if err := c.ShouldBindJSON(&proposal); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	_, interview, userType, ok := findInterview(c)
+	if !ok {
+		return
+	}
+	if interview.Status != models.InterviewProposed && interview.Status != models.InterviewConfirmed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only upcoming interviews can be rescheduled"})
+		return
+	}
+
+	if proposal.Mode != "" {
+		interview.Mode = proposal.Mode
+	}
+	if location := strings.TrimSpace(proposal.Location); location != "" {
+		interview.Location = location
+	}
+	if proposal.DurationMinutes != 0 {
+		interview.DurationMinutes = proposal.DurationMinutes
+	}
+	if !validInterviewSetup(c, interview) {
+		return
+	}
+
+	slots, ok := validInterviewSlots(c, proposal.Slots)
+	if !ok {
+		return
+	}
+
+	previousStatus := interview.Status
+	interview.Slots = slots
+	interview.ProposedBy = userType
+	interview.Status = models.InterviewProposed
+	interview.ScheduledAt = time.Time{}
+	interview.EndsAt = time.Time{}
+	interview.ReminderSentAt = time.Time{}
+	interview.UpdatedAt = time.Now()
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		if err := checkInterviewSlots(uow.Context(), interview); err != nil {
+			return err
+		}
+
+		result, err := config.DB.Collection("interviews").UpdateOne(uow.Context(),
+			bson.M{"_id": interview.ID, "status": previousStatus},
+			bson.M{
+				"$set": bson.M{
+					"mode":             interview.Mode,
+					"location":         interview.Location,
+					"duration_minutes": interview.DurationMinutes,
+					"slots":            interview.Slots,
+					"proposed_by":      interview.ProposedBy,
+					"status":           interview.Status,
+					"updated_at":       interview.UpdatedAt,
+				},
+				"$unset": bson.M{"scheduled_at": "", "ends_at": "", "reminder_sent_at": "", "reminder_channels": "", "reminder_claimed_until": ""},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errInterviewChanged
+		}
+
+		return uow.Publish(events.InterviewChanged{Interview: interview, PreviousStatus: previousStatus, Actor: userType})
+	})
+	if err != nil {
+		interviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, interview)
+}
+
+// CancelInterview godoc
+// @Summary Cancels an interview
+// @Description This endpoint lets either party call off an upcoming interview
+// @Tags interviews
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param interview_id path string true "Interview ID"
+// @Success 200 {object} models.Interview
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/interviews/{interview_id}/cancel [post]
+func CancelInterview(c *gin.Context) {
+	_, interview, userType, ok := findInterview(c)
+	if !ok {
+		return
+	}
+	if interview.Status != models.InterviewProposed && interview.Status != models.InterviewConfirmed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only upcoming interviews can be cancelled"})
+		return
+	}
+
+	previousStatus := interview.Status
+	interview.Status = models.InterviewCancelled
+	interview.UpdatedAt = time.Now()
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("interviews").UpdateOne(uow.Context(),
+			bson.M{"_id": interview.ID, "status": previousStatus},
+			bson.M{"$set": bson.M{"status": interview.Status, "updated_at": interview.UpdatedAt}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errInterviewChanged
+		}
+
+		return uow.Publish(events.InterviewChanged{Interview: interview, PreviousStatus: previousStatus, Actor: userType})
+	})
+	if err != nil {
+		interviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, interview)
+}
+
+// RecordInterviewOutcome godoc
+// @Summary Records the outcome of an interview
+// @Description This endpoint lets the employer record whether the housekeeper passed the interview. A failed interview rejects the hiring; a passed one lets it be approved.
+// @Tags interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param interview_id path string true "Interview ID"
+// @Param result body models.InterviewResult true "Outcome"
+// @Success 200 {object} models.Interview
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/interviews/{interview_id}/outcome [post]
+func RecordInterviewOutcome(c *gin.Context) {
+	var result models.InterviewResult
+	if err := c.ShouldBindJSON(&result); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiring, interview, userType, ok := findInterview(c)
+	if !ok {
+		return
+	}
+	if userType != "employer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the employer can record the outcome"})
+		return
+	}
+	if interview.Status != models.InterviewConfirmed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only confirmed interviews can have an outcome"})
+		return
+	}
+	if time.Now().Before(interview.ScheduledAt) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The interview has not taken place yet"})
+		return
+	}
+
+	previousStatus := interview.Status
+	interview.Status = models.InterviewCompleted
+	interview.Outcome = result.Outcome
+	interview.OutcomeNote = strings.TrimSpace(result.Note)
+	interview.UpdatedAt = time.Now()
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		updated, err := config.DB.Collection("interviews").UpdateOne(uow.Context(),
+			bson.M{"_id": interview.ID, "status": previousStatus},
+			bson.M{"$set": bson.M{
+				"status":       interview.Status,
+				"outcome":      interview.Outcome,
+				"outcome_note": interview.OutcomeNote,
+				"updated_at":   interview.UpdatedAt,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if updated.MatchedCount == 0 {
+			return errInterviewChanged
+		}
+
+		if err := uow.Publish(events.InterviewChanged{Interview: interview, PreviousStatus: previousStatus, Actor: userType}); err != nil {
+			return err
+		}
+
+		if interview.Outcome != models.InterviewFailed {
+			return nil
+		}
+
+		rejected, err := config.DB.Collection("hirings").UpdateOne(uow.Context(),
+			bson.M{"_id": hiring.ID, "status": models.Pending},
+			bson.M{"$set": bson.M{"status": models.Rejected, "update_at": interview.UpdatedAt}},
+		)
+		if err != nil {
+			return err
+		}
+		// The hiring may have been cancelled or decided in the meantime
+		if rejected.ModifiedCount == 0 {
+			return nil
+		}
+		hiring.Status = models.Rejected
+		hiring.UpdatedAt = interview.UpdatedAt
+		return uow.Publish(events.HiringStatusChanged{Hiring: hiring, PreviousStatus: models.Pending})
+	})
+	if err != nil {
+		interviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, interview)
+}
+
+// findInterview loads the hiring and interview in the path parameters for one
+// of the hiring's parties, writing the error response itself when it cannot
+func findInterview(c *gin.Context) (models.Hiring, models.Interview, string, bool) {
+	hiring, _, userType, ok := findPartyHiring(c, false)
+	if !ok {
+		return models.Hiring{}, models.Interview{}, "", false
+	}
+
+	interviewID, err := primitive.ObjectIDFromHex(c.Param("interview_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interview ID"})
+		return models.Hiring{}, models.Interview{}, "", false
+	}
+
+	var interview models.Interview
+	err = config.DB.Collection("interviews").FindOne(context.Background(),
+		bson.M{"_id": interviewID, "hiring_id": hiring.ID},
+	).Decode(&interview)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return models.Hiring{}, models.Interview{}, "", false
+	}
+
+	return hiring, interview, userType, true
+}
+
+func validInterviewSetup(c *gin.Context, interview models.Interview) bool {
+	switch interview.Mode {
+	case models.InPersonInterview:
+		if interview.Location == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "In-person interviews need a location"})
+			return false
+		}
+	case models.PhoneInterview:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Mode must be IN_PERSON or PHONE"})
+		return false
+	}
+	return true
+}
+
+// validInterviewSlots checks that every slot is in the future and returns
+// them sorted without duplicates
+func validInterviewSlots(c *gin.Context, slots []time.Time) ([]time.Time, bool) {
+	now := time.Now()
+	sorted := make([]time.Time, 0, len(slots))
+	for _, slot := range slots {
+		if !slot.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Interview slots must be in the future"})
+			return nil, false
+		}
+		sorted = append(sorted, slot.UTC())
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	unique := sorted[:0]
+	for i, slot := range sorted {
+		if i == 0 || !slot.Equal(sorted[i-1]) {
+			unique = append(unique, slot)
+		}
+	}
+	return unique, true
+}
+
+// checkInterviewSlots fails when one of the proposed slots overlaps another
+// confirmed interview of the housekeeper
+func checkInterviewSlots(ctx context.Context, interview models.Interview) error {
+	for _, slot := range interview.Slots {
+		if err := checkInterviewConflict(ctx, interview, slot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkInterviewConflict(ctx context.Context, interview models.Interview, start time.Time) error {
+	end := start.Add(time.Duration(interview.DurationMinutes) * time.Minute)
+	count, err := config.DB.Collection("interviews").CountDocuments(ctx, bson.M{
+		"_id":            bson.M{"$ne": interview.ID},
+		"housekeeper_id": interview.HousekeeperID,
+		"status":         models.InterviewConfirmed,
+		"scheduled_at":   bson.M{"$lt": end},
+		"ends_at":        bson.M{"$gt": start},
+	})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errInterviewConflict
+	}
+	return nil
+}
+
+// lockHousekeeperInterviews writes to the housekeeper inside the transaction,
+// so two transactions confirming interviews for the same housekeeper conflict
+// and one of them is retried and sees the other's interview
+func lockHousekeeperInterviews(ctx context.Context, housekeeperID primitive.ObjectID) error {
+	_, err := config.DB.Collection("housekeepers").UpdateOne(ctx,
+		bson.M{"_id": housekeeperID},
+		bson.M{"$currentDate": bson.M{"interviews_updated_at": true}},
+	)
+	return err
+}
+
+// openInterviewCheck fails with errInterviewOpen while an interview of the
+// hiring has not taken place, since approving then would skip it
+func openInterviewCheck(ctx context.Context, hiringID primitive.ObjectID) error {
+	count, err := config.DB.Collection("interviews").CountDocuments(ctx, bson.M{
+		"hiring_id": hiringID,
+		"status":    bson.M{"$in": models.OpenInterviewStatuses},
+	})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errInterviewOpen
+	}
+	return nil
+}
+
+func interviewError(c *gin.Context, err error) {
+	switch err {
+	case errInterviewConflict:
+		c.JSON(http.StatusConflict, gin.H{"error": "The housekeeper has another interview at that time"})
+	case errInterviewChanged:
+		c.JSON(http.StatusConflict, gin.H{"error": "The interview was updated in the meantime, please try again"})
+	case errInterviewOpen:
+		c.JSON(http.StatusConflict, gin.H{"error": "This hiring already has an upcoming interview"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update interview"})
+	}
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/invoices"
+	"backend/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetEmployerInvoices godoc
+// @Summary Lists an employer's invoices
+// @Description This endpoint lists the placement fee invoices of an employer, newest first, optionally filtered by status. Employers can only list their own invoices.
+// @Tags invoices
+// @Produce json
+// @Param id path string true "Employer ID"
+// @Param status query string false "Invoice status (UNPAID, PAID, REFUNDED, VOID)"
+// @Success 200 {array} models.Invoice
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /employers/{id}/invoices [get]
+func GetEmployerInvoices(c *gin.Context) {
+	employerID, ok := invoiceEmployer(c)
+	if !ok {
+		return
+	}
+
+	filter := bson.M{"employer_id": employerID}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "issued_at", Value: -1}})
+	cursor, err := config.DB.Collection("invoices").Find(context.Background(), filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoices"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	list := []models.Invoice{}
+	if err := cursor.All(context.Background(), &list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode invoices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DownloadInvoice godoc
+// @Summary Downloads an invoice as a PDF
+// @Description This endpoint renders an invoice of an employer as a PDF, showing its current payment status
+// @Tags invoices
+// @Produce application/pdf
+// @Param id path string true "Employer ID"
+// @Param invoice_id path string true "Invoice ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /employers/{id}/invoices/{invoice_id}/pdf [get]
+func DownloadInvoice(c *gin.Context) {
+	employerID, ok := invoiceEmployer(c)
+	if !ok {
+		return
+	}
+
+	invoiceID, err := primitive.ObjectIDFromHex(c.Param("invoice_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+
+	var invoice models.Invoice
+	err = config.DB.Collection("invoices").FindOne(context.Background(),
+		bson.M{"_id": invoiceID, "employer_id": employerID}).Decode(&invoice)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice"})
+		}
+		return
+	}
+
+	var employer models.Employer
+	if err := config.DB.Collection("employers").FindOne(context.Background(), bson.M{"_id": employerID}).Decode(&employer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employer"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="invoice-`+invoice.Number+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", invoices.Document(invoice, employer))
+}
+
+// invoiceEmployer returns the employer in the path if the current user is
+// that employer or an admin, otherwise it writes the error response
+func invoiceEmployer(c *gin.Context) (primitive.ObjectID, bool) {
+	employerID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employer ID"})
+		return primitive.NilObjectID, false
+	}
+
+	userID, userType, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return primitive.NilObjectID, false
+	}
+	if userType != "admin" && (userType != "employer" || userID != employerID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own invoices"})
+		return primitive.NilObjectID, false
+	}
+	return employerID, true
+}
+
+// CreateFeeRule godoc
+// @Summary Adds a placement fee rule
+// @Description This endpoint adds a placement fee rule: a flat amount, or a percent of the monthly salary offered with an optional minimum. New invoices use the newest rule for the housekeeper's category, falling back to the newest rule without a category. Existing invoices keep their price.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param rule body models.FeeRule true "Fee rule"
+// @Success 201 {object} models.FeeRule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/fee-rules [post]
+func CreateFeeRule(c *gin.Context) {
+	var rule models.FeeRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := invoices.Validate(rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fee rule: " + err.Error()})
+		return
+	}
+
+	userID, _, _ := currentUser(c)
+	rule.ID = primitive.NilObjectID
+	rule.CreatedBy = userID
+	rule.CreatedAt = time.Now()
+
+	result, err := config.DB.Collection("fee_rules").InsertOne(context.Background(), rule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save fee rule"})
+		return
+	}
+	rule.ID = result.InsertedID.(primitive.ObjectID)
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetFeeRules godoc
+// @Summary Lists placement fee rules
+// @Description This endpoint lists the placement fee rules, newest first
+// @Tags invoices
+// @Produce json
+// @Success 200 {array} models.FeeRule
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/fee-rules [get]
+func GetFeeRules(c *gin.Context) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := config.DB.Collection("fee_rules").Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch fee rules"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	rules := []models.FeeRule{}
+	if err := cursor.All(context.Background(), &rules); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode fee rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetJobRuns godoc
+// @Summary Lists scheduled jobs
+// @Description This endpoint retrieves every scheduled job with its schedule, next run and the result of its last run
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.JobRun
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/jobs [get]
+func GetJobRuns(c *gin.Context) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := config.DB.Collection("job_runs").Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	runs := []models.JobRun{}
+	if err := cursor.All(context.Background(), &runs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
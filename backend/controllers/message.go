@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"backend/moderation"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultMessagePageSize = 30
+	maxMessagePageSize     = 100
+)
+
+// SendMessage godoc
+// @Summary Sends a message about a hiring
+// @Description This endpoint sends a message to the other party of a hiring. Phone numbers are hidden until the hiring is approved, so the parties keep talking on the platform until then.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param message body models.MessageRequest true "Message"
+// @Success 201 {object} models.Message
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/messages [post]
+func SendMessage(c *gin.Context) {
+	var request models.MessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	body := strings.TrimSpace(request.Body)
+	if body == "" && len(request.Attachments) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A message needs a body or an attachment"})
+		return
+	}
+
+	hiring, userID, userType, ok := findPartyHiring(c, false)
+	if !ok {
+		return
+	}
+	if hiring.Status == models.Rejected || hiring.Status == models.Cancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "The conversation of a rejected or cancelled hiring is closed"})
+		return
+	}
+
+	message := models.Message{
+		HiringID:    hiring.ID,
+		SenderID:    userID,
+		SenderType:  userType,
+		Body:        body,
+		Attachments: request.Attachments,
+		CreatedAt:   time.Now(),
+	}
+	if hiring.Status == models.Pending {
+		message.Body = moderation.RedactPhoneNumbers(body)
+		message.Redacted = message.Body != body
+	}
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("messages").InsertOne(uow.Context(), message)
+		if err != nil {
+			return err
+		}
+		message.ID = result.InsertedID.(primitive.ObjectID)
+
+		return uow.Publish(events.MessageSent{Message: message, Hiring: hiring})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+// GetMessages godoc
+// @Summary Lists the messages of a hiring
+// @Description This endpoint retrieves the conversation of a hiring, newest first, together with the number of messages the caller has not read
+// @Tags messages
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param before query string false "Only return messages older than this message ID"
+// @Param limit query int false "Page size, at most 100"
+// @Success 200 {object} models.MessagesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/messages [get]
+func GetMessages(c *gin.Context) {
+	hiring, _, userType, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+
+	limit := defaultMessagePageSize
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxMessagePageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+
+	filter := bson.M{"hiring_id": hiring.ID}
+	if before := c.Query("before"); before != "" {
+		beforeID, err := primitive.ObjectIDFromHex(before)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid before cursor"})
+			return
+		}
+		filter["_id"] = bson.M{"$lt": beforeID}
+	}
+
+	collection := config.DB.Collection("messages")
+	// One extra message tells whether there is an older page
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(limit + 1))
+	cursor, err := collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch messages"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	messages := []models.Message{}
+	if err := cursor.All(context.Background(), &messages); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode messages"})
+		return
+	}
+
+	response := models.MessagesResponse{Messages: messages}
+	if len(messages) > limit {
+		response.Messages = messages[:limit]
+		response.NextBefore = messages[limit-1].ID.Hex()
+	}
+
+	if userType != "admin" {
+		response.Unread, err = collection.CountDocuments(context.Background(), unreadFilter(hiring.ID, userType))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count unread messages"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// MarkMessagesRead godoc
+// @Summary Marks a conversation as read
+// @Description This endpoint marks every message the other party sent about a hiring as read, which the sender sees as a read receipt
+// @Tags messages
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/messages/read [post]
+func MarkMessagesRead(c *gin.Context) {
+	hiring, _, userType, ok := findPartyHiring(c, false)
+	if !ok {
+		return
+	}
+
+	result, err := config.DB.Collection("messages").UpdateMany(context.Background(),
+		unreadFilter(hiring.ID, userType),
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark messages as read"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Messages marked as read", "read": result.ModifiedCount})
+}
+
+// unreadFilter matches the messages of a hiring that the given party received
+// and has not read
+func unreadFilter(hiringID primitive.ObjectID, userType string) bson.M {
+	return bson.M{
+		"hiring_id":   hiringID,
+		"sender_type": bson.M{"$ne": userType},
+		"read_at":     bson.M{"$exists": false},
+	}
+}
@@ -0,0 +1,517 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReportReview godoc
+// @Summary Reports a review
+// @Description This endpoint lets any signed-in user report an abusive, fake or otherwise inappropriate review, or its reply, to the moderators. Set target to REPLY to report the reply.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param report body models.ReviewReport true "Report"
+// @Success 201 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ratings/{id}/report [post]
+func ReportReview(c *gin.Context) {
+	var report models.ReviewReport
+	if err := c.ShouldBindJSON(&report); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, ok := findReview(c)
+	if !ok {
+		return
+	}
+
+	if report.Target == "" {
+		report.Target = models.ReportTargetReview
+	}
+	if report.Target == models.ReportTargetReply && review.Reply == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The review has no reply"})
+		return
+	}
+
+	fileReport(c, report, "reviews", review.ID)
+}
+
+// ReportEmployerReview godoc
+// @Summary Reports a review of an employer
+// @Description This endpoint lets any signed-in user report an abusive, fake or otherwise inappropriate review of an employer to the moderators
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Employer review ID"
+// @Param report body models.ReviewReport true "Report"
+// @Success 201 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ratings/employer/{id}/report [post]
+func ReportEmployerReview(c *gin.Context) {
+	var report models.ReviewReport
+	if err := c.ShouldBindJSON(&report); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, ok := findEmployerReview(c)
+	if !ok {
+		return
+	}
+
+	report.Target = models.ReportTargetEmployerReview
+	fileReport(c, report, "employer_reviews", review.ID)
+}
+
+// fileReport stores a report about the review reviewID in collection and
+// counts it as open on the review
+func fileReport(c *gin.Context, report models.ReviewReport, collection string, reviewID primitive.ObjectID) {
+	userID, userType, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return
+	}
+
+	report.ReviewID = reviewID
+	report.ReporterID = userID
+	report.ReporterType = userType
+	report.Resolved = false
+	report.CreatedAt = time.Now()
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("review_reports").InsertOne(uow.Context(), report)
+		if err != nil {
+			return err
+		}
+		report.ID = result.InsertedID.(primitive.ObjectID)
+
+		_, err = config.DB.Collection(collection).UpdateOne(uow.Context(),
+			bson.M{"_id": reviewID},
+			bson.M{"$inc": bson.M{"open_reports": 1}},
+		)
+		return err
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "You have already reported this review"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to report review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Review reported successfully", "id": report.ID})
+}
+
+// GetModerationQueue godoc
+// @Summary Lists reviews waiting for moderation
+// @Description This endpoint retrieves reviews whose text or reply was flagged by the word filter or PII detection and reviews with open reports, oldest first
+// @Tags moderation
+// @Produce json
+// @Success 200 {array} models.ModerationQueueItem
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/moderation/reviews [get]
+func GetModerationQueue(c *gin.Context) {
+	filter := bson.M{"$or": []bson.M{
+		{"status": models.ReviewPendingReview},
+		{"reply.status": models.ReviewPendingReview},
+		{"open_reports": bson.M{"$gt": 0}},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(100)
+
+	cursor, err := config.DB.Collection("reviews").Find(context.Background(), filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch moderation queue"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	var reviews []models.Review
+	if err := cursor.All(context.Background(), &reviews); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode moderation queue"})
+		return
+	}
+
+	reviewIDs := make([]primitive.ObjectID, len(reviews))
+	for i, review := range reviews {
+		reviewIDs[i] = review.ID
+	}
+	reportsByReview, ok := openReports(c, reviewIDs)
+	if !ok {
+		return
+	}
+
+	queue := make([]models.ModerationQueueItem, len(reviews))
+	for i, review := range reviews {
+		queue[i] = models.ModerationQueueItem{Review: review, Reports: reportsByReview[review.ID]}
+		if queue[i].Reports == nil {
+			queue[i].Reports = []models.ReviewReport{}
+		}
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// GetEmployerModerationQueue godoc
+// @Summary Lists reviews of employers waiting for moderation
+// @Description This endpoint retrieves reviews of employers flagged by the word filter or PII detection and those with open reports, oldest first
+// @Tags moderation
+// @Produce json
+// @Success 200 {array} models.EmployerModerationQueueItem
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/moderation/employer-reviews [get]
+func GetEmployerModerationQueue(c *gin.Context) {
+	filter := bson.M{"$or": []bson.M{
+		{"status": models.ReviewPendingReview},
+		{"open_reports": bson.M{"$gt": 0}},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(100)
+
+	cursor, err := config.DB.Collection("employer_reviews").Find(context.Background(), filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch moderation queue"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	var reviews []models.EmployerReview
+	if err := cursor.All(context.Background(), &reviews); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode moderation queue"})
+		return
+	}
+
+	reviewIDs := make([]primitive.ObjectID, len(reviews))
+	for i, review := range reviews {
+		reviewIDs[i] = review.ID
+	}
+	reportsByReview, ok := openReports(c, reviewIDs)
+	if !ok {
+		return
+	}
+
+	queue := make([]models.EmployerModerationQueueItem, len(reviews))
+	for i, review := range reviews {
+		queue[i] = models.EmployerModerationQueueItem{Review: review, Reports: reportsByReview[review.ID]}
+		if queue[i].Reports == nil {
+			queue[i].Reports = []models.ReviewReport{}
+		}
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// openReports loads the unresolved reports of the reviews, grouped by review.
+// It writes the error response itself when it fails.
+func openReports(c *gin.Context, reviewIDs []primitive.ObjectID) (map[primitive.ObjectID][]models.ReviewReport, bool) {
+	cursor, err := config.DB.Collection("review_reports").Find(context.Background(), bson.M{
+		"review_id": bson.M{"$in": reviewIDs},
+		"resolved":  false,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review reports"})
+		return nil, false
+	}
+	defer cursor.Close(context.Background())
+
+	var reports []models.ReviewReport
+	if err := cursor.All(context.Background(), &reports); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode review reports"})
+		return nil, false
+	}
+
+	reportsByReview := map[primitive.ObjectID][]models.ReviewReport{}
+	for _, report := range reports {
+		reportsByReview[report.ReviewID] = append(reportsByReview[report.ReviewID], report)
+	}
+	return reportsByReview, true
+}
+
+// HideReview godoc
+// @Summary Hides a review
+// @Description This endpoint hides a review from listings and rating aggregates and resolves its open reports
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param action body models.ModerationAction false "Moderator note"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/moderation/reviews/{id}/hide [post]
+func HideReview(c *gin.Context) {
+	moderateReview(c, models.ReviewHidden)
+}
+
+// RestoreReview godoc
+// @Summary Restores a review
+// @Description This endpoint makes a flagged or hidden review visible again and resolves its open reports
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param action body models.ModerationAction false "Moderator note"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/moderation/reviews/{id}/restore [post]
+func RestoreReview(c *gin.Context) {
+	moderateReview(c, models.ReviewVisible)
+}
+
+func moderateReview(c *gin.Context, status models.ReviewStatus) {
+	action, ok := bindModerationAction(c)
+	if !ok {
+		return
+	}
+
+	review, ok := findReview(c)
+	if !ok {
+		return
+	}
+
+	previousStatus := review.Status
+	review.Status = status
+	review.ModerationNote = action.Note
+	review.ModeratedAt = time.Now()
+	review.OpenReports = 0
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		_, err := config.DB.Collection("reviews").UpdateOne(uow.Context(),
+			bson.M{"_id": review.ID},
+			bson.M{"$set": bson.M{
+				"status":          review.Status,
+				"moderation_note": review.ModerationNote,
+				"moderated_at":    review.ModeratedAt,
+				"open_reports":    0,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = config.DB.Collection("review_reports").UpdateMany(uow.Context(),
+			bson.M{"review_id": review.ID, "resolved": false},
+			bson.M{"$set": bson.M{"resolved": true}},
+		)
+		if err != nil {
+			return err
+		}
+
+		return uow.Publish(events.ReviewModerated{Review: review, PreviousStatus: previousStatus})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to moderate review"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review moderated successfully"})
+}
+
+// HideEmployerReview godoc
+// @Summary Hides a review of an employer
+// @Description This endpoint hides a review of an employer from listings and the employer's rating and resolves its open reports
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Employer review ID"
+// @Param action body models.ModerationAction false "Moderator note"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/moderation/employer-reviews/{id}/hide [post]
+func HideEmployerReview(c *gin.Context) {
+	moderateEmployerReview(c, models.ReviewHidden)
+}
+
+// RestoreEmployerReview godoc
+// @Summary Restores a review of an employer
+// @Description This endpoint makes a flagged or hidden review of an employer visible again and resolves its open reports
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Employer review ID"
+// @Param action body models.ModerationAction false "Moderator note"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/moderation/employer-reviews/{id}/restore [post]
+func RestoreEmployerReview(c *gin.Context) {
+	moderateEmployerReview(c, models.ReviewVisible)
+}
+
+func moderateEmployerReview(c *gin.Context, status models.ReviewStatus) {
+	action, ok := bindModerationAction(c)
+	if !ok {
+		return
+	}
+
+	review, ok := findEmployerReview(c)
+	if !ok {
+		return
+	}
+
+	previousStatus := review.Status
+	review.Status = status
+	review.ModerationNote = action.Note
+	review.ModeratedAt = time.Now()
+	review.OpenReports = 0
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		_, err := config.DB.Collection("employer_reviews").UpdateOne(uow.Context(),
+			bson.M{"_id": review.ID},
+			bson.M{"$set": bson.M{
+				"status":          review.Status,
+				"moderation_note": review.ModerationNote,
+				"moderated_at":    review.ModeratedAt,
+				"open_reports":    0,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = config.DB.Collection("review_reports").UpdateMany(uow.Context(),
+			bson.M{"review_id": review.ID, "resolved": false},
+			bson.M{"$set": bson.M{"resolved": true}},
+		)
+		if err != nil {
+			return err
+		}
+
+		return uow.Publish(events.EmployerReviewModerated{Review: review, PreviousStatus: previousStatus})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to moderate review"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review moderated successfully"})
+}
+
+// HideReviewReply godoc
+// @Summary Hides the reply to a review
+// @Description This endpoint hides the housekeeper's reply to a review and resolves the open reports about it. The review itself is not changed.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param action body models.ModerationAction false "Moderator note"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/moderation/reviews/{id}/reply/hide [post]
+func HideReviewReply(c *gin.Context) {
+	moderateReply(c, models.ReviewHidden)
+}
+
+// RestoreReviewReply godoc
+// @Summary Restores the reply to a review
+// @Description This endpoint makes a flagged or hidden reply to a review visible again and resolves the open reports about it
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param action body models.ModerationAction false "Moderator note"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/moderation/reviews/{id}/reply/restore [post]
+func RestoreReviewReply(c *gin.Context) {
+	moderateReply(c, models.ReviewVisible)
+}
+
+func moderateReply(c *gin.Context, status models.ReviewStatus) {
+	action, ok := bindModerationAction(c)
+	if !ok {
+		return
+	}
+
+	review, ok := findReview(c)
+	if !ok {
+		return
+	}
+	if review.Reply == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The review has no reply"})
+		return
+	}
+
+	previousStatus := review.Reply.Status
+	review.Reply.Status = status
+	review.Reply.ModerationNote = action.Note
+	review.Reply.ModeratedAt = time.Now()
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		resolved, err := config.DB.Collection("review_reports").UpdateMany(uow.Context(),
+			bson.M{"review_id": review.ID, "target": models.ReportTargetReply, "resolved": false},
+			bson.M{"$set": bson.M{"resolved": true}},
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = config.DB.Collection("reviews").UpdateOne(uow.Context(),
+			bson.M{"_id": review.ID, "reply": bson.M{"$exists": true}},
+			bson.M{
+				"$set": bson.M{
+					"reply.status":          review.Reply.Status,
+					"reply.moderation_note": review.Reply.ModerationNote,
+					"reply.moderated_at":    review.Reply.ModeratedAt,
+				},
+				"$inc": bson.M{"open_reports": -resolved.ModifiedCount},
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		return uow.Publish(events.ReviewReplyModerated{Review: review, PreviousStatus: previousStatus})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to moderate reply"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reply moderated successfully"})
+}
+
+// bindModerationAction reads the optional moderator note, writing the error
+// response itself when the body is invalid
+func bindModerationAction(c *gin.Context) (models.ModerationAction, bool) {
+	var action models.ModerationAction
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&action); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return action, false
+		}
+	}
+	return action, true
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// UpdateSMSPreferences godoc
+// @Summary Opts in or out of SMS notifications
+// @Description This endpoint lets the authenticated housekeeper or employer choose whether to receive SMS notifications
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param preferences body models.SMSPreferences true "SMS preferences"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications/sms [put]
+func UpdateSMSPreferences(c *gin.Context) {
+	userID, userType, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return
+	}
+
+	var preferences models.SMSPreferences
+	if err := c.ShouldBindJSON(&preferences); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var collection string
+	switch userType {
+	case "housekeeper":
+		collection = "housekeepers"
+	case "employer":
+		collection = "employers"
+	default:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only employers and housekeepers receive SMS notifications"})
+		return
+	}
+
+	result, err := config.DB.Collection(collection).UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"sms_opt_in": preferences.OptIn, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update SMS preferences"})
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "SMS preferences updated successfully"})
+}
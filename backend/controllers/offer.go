@@ -0,0 +1,327 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	errOfferClosed = errors.New("offer is no longer open")
+	errOfferOpen   = errors.New("an offer is waiting for an answer")
+)
+
+// CreateOffer godoc
+// @Summary Proposes terms for a hiring
+// @Description This endpoint lets the employer revise or the housekeeper counter the terms of a pending hiring. Terms left out are carried over from the open proposal, or else the accepted offer or the original request, and the proposal replaces any open one.
+// @Tags offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param terms body models.HiringTerms true "Proposed terms"
+// @Success 201 {object} models.Offer
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/offers [post]
+func CreateOffer(c *gin.Context) {
+	var terms models.HiringTerms
+	if err := c.ShouldBindJSON(&terms); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiring, _, userType, ok := findPartyHiring(c, false)
+	if !ok {
+		return
+	}
+	if hiring.Status != models.Pending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Terms can only be negotiated while the hiring is pending"})
+		return
+	}
+
+	current, err := currentTerms(context.Background(), hiring)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current terms"})
+		return
+	}
+	if terms.SalaryOffer == 0 {
+		terms.SalaryOffer = current.SalaryOffer
+	}
+	if terms.StartDate.IsZero() {
+		terms.StartDate = current.StartDate
+	} else if terms.StartDate.Before(time.Now().Truncate(24 * time.Hour)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The start date must not be in the past"})
+		return
+	}
+	if terms.EmploymentType == "" {
+		terms.EmploymentType = current.EmploymentType
+	}
+	if terms.Requirements == "" {
+		terms.Requirements = current.Requirements
+	}
+	if terms.Equal(current) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The proposal does not change any terms"})
+		return
+	}
+
+	offer := models.Offer{
+		HiringID:   hiring.ID,
+		ProposedBy: userType,
+		Terms:      terms,
+		Status:     models.OfferOpen,
+		CreatedAt:  time.Now(),
+	}
+
+	err = events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		collection := config.DB.Collection("offers")
+		_, err := collection.UpdateMany(uow.Context(),
+			bson.M{"hiring_id": hiring.ID, "status": models.OfferOpen},
+			bson.M{"$set": bson.M{"status": models.OfferSuperseded}},
+		)
+		if err != nil {
+			return err
+		}
+
+		result, err := collection.InsertOne(uow.Context(), offer)
+		if err != nil {
+			return err
+		}
+		offer.ID = result.InsertedID.(primitive.ObjectID)
+
+		return uow.Publish(events.OfferMade{Offer: offer, Hiring: hiring})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create offer"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, offer)
+}
+
+// GetOffers godoc
+// @Summary Lists the offers of a hiring
+// @Description This endpoint retrieves the negotiation history of a hiring, oldest first
+// @Tags offers
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Success 200 {array} models.Offer
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/offers [get]
+func GetOffers(c *gin.Context) {
+	hiring, _, _, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := config.DB.Collection("offers").Find(context.Background(), bson.M{"hiring_id": hiring.ID}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch offers"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	offers := []models.Offer{}
+	if err := cursor.All(context.Background(), &offers); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode offers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+// AcceptOffer godoc
+// @Summary Accepts the latest offer
+// @Description This endpoint accepts the open offer of a hiring. Only the party that did not propose it can accept it; its terms are copied into the hiring when the hiring is approved.
+// @Tags offers
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param offer_id path string true "Offer ID"
+// @Success 200 {object} models.Offer
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/offers/{offer_id}/accept [post]
+func AcceptOffer(c *gin.Context) {
+	respondToOffer(c, models.OfferAccepted)
+}
+
+// DeclineOffer godoc
+// @Summary Declines the latest offer
+// @Description This endpoint declines the open offer of a hiring. Only the party that did not propose it can decline it.
+// @Tags offers
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param offer_id path string true "Offer ID"
+// @Success 200 {object} models.Offer
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/offers/{offer_id}/decline [post]
+func DeclineOffer(c *gin.Context) {
+	respondToOffer(c, models.OfferDeclined)
+}
+
+func respondToOffer(c *gin.Context, status models.OfferStatus) {
+	hiring, _, userType, ok := findPartyHiring(c, false)
+	if !ok {
+		return
+	}
+
+	offerID, err := primitive.ObjectIDFromHex(c.Param("offer_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offer ID"})
+		return
+	}
+
+	var offer models.Offer
+	err = config.DB.Collection("offers").FindOne(context.Background(),
+		bson.M{"_id": offerID, "hiring_id": hiring.ID},
+	).Decode(&offer)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+
+	if offer.ProposedBy == userType {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot respond to your own offer"})
+		return
+	}
+	if hiring.Status != models.Pending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Terms can only be negotiated while the hiring is pending"})
+		return
+	}
+	// Newer proposals supersede older ones, so the open offer is the latest
+	if offer.Status != models.OfferOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only the latest open offer can be answered"})
+		return
+	}
+
+	offer.Status = status
+	offer.RespondedAt = time.Now()
+
+	err = events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("offers").UpdateOne(uow.Context(),
+			bson.M{"_id": offer.ID, "status": models.OfferOpen},
+			bson.M{"$set": bson.M{"status": offer.Status, "responded_at": offer.RespondedAt}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errOfferClosed
+		}
+
+		if status == models.OfferAccepted {
+			result, err := config.DB.Collection("hirings").UpdateOne(uow.Context(),
+				bson.M{"_id": hiring.ID, "status": models.Pending},
+				bson.M{"$set": bson.M{"accepted_offer_id": offer.ID}},
+			)
+			if err != nil {
+				return err
+			}
+			if result.MatchedCount == 0 {
+				return errHiringChanged
+			}
+			hiring.AcceptedOfferID = offer.ID
+		}
+
+		return uow.Publish(events.OfferResponded{Offer: offer, Hiring: hiring})
+	})
+	if err != nil {
+		if err == errOfferClosed {
+			c.JSON(http.StatusConflict, gin.H{"error": "Only the latest open offer can be answered"})
+		} else if err == errHiringChanged {
+			c.JSON(http.StatusConflict, gin.H{"error": "Terms can only be negotiated while the hiring is pending"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to respond to offer"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// currentTerms returns the terms on the table for hiring: those of the open
+// proposal when there is one, otherwise those of the accepted offer, or of the
+// original request when no offer was accepted. Declined and superseded
+// proposals never count.
+func currentTerms(ctx context.Context, hiring models.Hiring) (models.HiringTerms, error) {
+	var offer models.Offer
+	err := config.DB.Collection("offers").FindOne(ctx,
+		bson.M{"hiring_id": hiring.ID, "status": models.OfferOpen},
+	).Decode(&offer)
+	if err == nil {
+		return offer.Terms, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return models.HiringTerms{}, err
+	}
+
+	if !hiring.AcceptedOfferID.IsZero() {
+		err := config.DB.Collection("offers").FindOne(ctx, bson.M{"_id": hiring.AcceptedOfferID}).Decode(&offer)
+		return offer.Terms, err
+	}
+
+	return models.HiringTerms{
+		SalaryOffer:    hiring.SalaryOffer,
+		StartDate:      hiring.StartDate,
+		EmploymentType: hiring.EmploymentType,
+		Requirements:   hiring.Requirements,
+	}, nil
+}
+
+// acceptedTerms returns the update that copies the terms of a hiring's
+// accepted offer into the hiring. It fails with errOfferOpen while a proposal
+// is still waiting for an answer, since approving then would ignore it.
+func acceptedTerms(ctx context.Context, hiring models.Hiring) (bson.M, error) {
+	count, err := config.DB.Collection("offers").CountDocuments(ctx,
+		bson.M{"hiring_id": hiring.ID, "status": models.OfferOpen})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errOfferOpen
+	}
+
+	if hiring.AcceptedOfferID.IsZero() {
+		return bson.M{}, nil
+	}
+
+	var offer models.Offer
+	err = config.DB.Collection("offers").FindOne(ctx, bson.M{"_id": hiring.AcceptedOfferID}).Decode(&offer)
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{
+		"salary_offer":    offer.Terms.SalaryOffer,
+		"start_date":      offer.Terms.StartDate,
+		"employment_type": offer.Terms.EmploymentType,
+		"requirements":    offer.Terms.Requirements,
+	}, nil
+}
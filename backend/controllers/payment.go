@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/payments"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// HandlePaymentWebhook godoc
+// @Summary Receives a payment provider callback
+// @Description This endpoint receives payment outcomes from a payment provider (fake, chapa or telebirr). The callback is verified with the provider's signature and applied once, however often it is delivered.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "Payment provider"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /payments/webhooks/{provider} [post]
+func HandlePaymentWebhook(c *gin.Context) {
+	provider, err := payments.Provider(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown payment provider"})
+		return
+	}
+
+	// The signature covers the raw body, so it is read before decoding
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	event, err := provider.ParseWebhook(c.Request.Header, body)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid callback: " + err.Error()})
+		}
+		return
+	}
+
+	applyPaymentWebhook(c, provider.Name(), event, body)
+}
+
+// PayFakePayment godoc
+// @Summary Pays a payment with the fake provider
+// @Description This endpoint is the checkout page of the fake payment provider, used in development. It reports the payment as paid, as a real provider's callback would. It is only available while the fake provider is active, and only to the employer who owes the payment.
+// @Tags payments
+// @Produce json
+// @Param reference path string true "Payment reference"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /payments/fake/{reference}/pay [post]
+func PayFakePayment(c *gin.Context) {
+	if payments.Active == nil || payments.Active.Name() != "fake" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The fake payment provider is not active"})
+		return
+	}
+
+	var payment models.Payment
+	err := config.DB.Collection("payments").FindOne(context.Background(),
+		bson.M{"provider": "fake", "reference": c.Param("reference")}).Decode(&payment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payment"})
+		}
+		return
+	}
+
+	userID, userType, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user"})
+		return
+	}
+	if userType != "employer" || payment.EmployerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the employer who owes this payment can pay it"})
+		return
+	}
+
+	applyPaymentWebhook(c, "fake", payments.WebhookEvent{
+		ID:                "pay:" + payment.Reference,
+		Reference:         payment.Reference,
+		ProviderReference: "fake-" + payment.ID.Hex(),
+		Status:            payments.WebhookSucceeded,
+		Amount:            payment.Amount,
+	}, nil)
+}
+
+func applyPaymentWebhook(c *gin.Context, provider string, event payments.WebhookEvent, body []byte) {
+	err := payments.HandleWebhook(context.Background(), provider, event, body)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	case errors.Is(err, payments.ErrPaymentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+	case errors.Is(err, payments.ErrAmountMismatch):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Paid amount does not match the payment"})
+	case errors.Is(err, payments.ErrPaymentConflict), mongo.IsDuplicateKeyError(err):
+		c.JSON(http.StatusConflict, gin.H{"error": "The payment was updated in the meantime, please retry"})
+	default:
+		log.Printf("Error handling %s payment callback %s: %v", provider, event.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process callback"})
+	}
+}
+
+// GetHiringPayments godoc
+// @Summary Lists the payments of a hiring
+// @Description This endpoint lists the payments of a hiring, such as its placement fee, with their status and checkout link
+// @Tags payments
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Success 200 {array} models.Payment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/payments [get]
+func GetHiringPayments(c *gin.Context) {
+	hiring, _, userType, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+	if userType == "housekeeper" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the employer can view payments"})
+		return
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := config.DB.Collection("payments").Find(context.Background(), bson.M{"hiring_id": hiring.ID}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payments"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	list := []models.Payment{}
+	if err := cursor.All(context.Background(), &list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode payments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// RefundPayment godoc
+// @Summary Refunds a payment
+// @Description This endpoint lets an admin refund a paid placement fee through its provider, whether it is still held in escrow or already released
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Param refund body models.RefundRequest true "Refund"
+// @Success 200 {object} models.Payment
+// @Success 202 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /admin/payments/{id}/refund [post]
+func RefundPayment(c *gin.Context) {
+	paymentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		return
+	}
+
+	var request models.RefundRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payment, err := payments.Refund(context.Background(), paymentID, request.Reason)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, payment)
+	case errors.Is(err, payments.ErrPaymentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+	case errors.Is(err, payments.ErrNotRefundable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Only paid payments can be refunded, this one is " + string(payment.Status)})
+	case errors.Is(err, payments.ErrPaymentConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "The payment was updated in the meantime, please try again"})
+	case errors.Is(err, payments.ErrRefundPending):
+		c.JSON(http.StatusAccepted, gin.H{"message": "The refund was sent to the provider and will be recorded shortly"})
+	default:
+		log.Printf("Error refunding payment %s: %v", paymentID.Hex(), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "The payment provider could not refund the payment"})
+	}
+}
+
+// GetLedgerAccounts godoc
+// @Summary Lists ledger accounts
+// @Description This endpoint lists the ledger accounts with their balance in santim, debits minus credits. Escrow and revenue accounts carry negative balances.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.LedgerAccount
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/ledger/accounts [get]
+func GetLedgerAccounts(c *gin.Context) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := config.DB.Collection("ledger_accounts").Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ledger accounts"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	accounts := []models.LedgerAccount{}
+	if err := cursor.All(context.Background(), &accounts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode ledger accounts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+// GetLedgerTransactions godoc
+// @Summary Lists ledger transactions
+// @Description This endpoint lists the latest 100 ledger transactions, newest first, optionally only those posting to one account
+// @Tags admin
+// @Produce json
+// @Param account query string false "Account, such as revenue:placement_fees"
+// @Success 200 {array} models.LedgerTransaction
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/ledger/transactions [get]
+func GetLedgerTransactions(c *gin.Context) {
+	filter := bson.M{}
+	if account := c.Query("account"); account != "" {
+		filter["accounts"] = account
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(100)
+	cursor, err := config.DB.Collection("ledger_transactions").Find(context.Background(), filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ledger transactions"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	transactions := []models.LedgerTransaction{}
+	if err := cursor.All(context.Background(), &transactions); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode ledger transactions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
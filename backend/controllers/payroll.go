@@ -0,0 +1,328 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/payroll"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetPayroll godoc
+// @Summary Lists the payroll of a placement
+// @Description This endpoint lists the monthly salary records of a placement, newest first, with tax, pension, deductions, advances, net pay and whether the salary was paid
+// @Tags payroll
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Success 200 {array} models.SalaryRecord
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/payroll [get]
+func GetPayroll(c *gin.Context) {
+	hiring, _, _, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "period", Value: -1}})
+	cursor, err := config.DB.Collection("salary_records").Find(context.Background(), bson.M{"hiring_id": hiring.ID}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payroll"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	records := []models.SalaryRecord{}
+	if err := cursor.All(context.Background(), &records); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode payroll"})
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// OpenPayrollPeriod godoc
+// @Summary Opens a payroll period
+// @Description This endpoint lets the employer or an admin open the salary record of a month, such as 2026-10, for example to record an earlier month. The current month is opened automatically.
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param period body models.PayrollPeriodRequest true "Period"
+// @Success 201 {object} models.SalaryRecord
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/payroll [post]
+func OpenPayrollPeriod(c *gin.Context) {
+	var request models.PayrollPeriodRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiring, ok := findPayrollHiring(c)
+	if !ok {
+		return
+	}
+
+	record, err := payroll.Open(context.Background(), hiring, request.Period)
+	if err != nil {
+		payrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// AddSalaryAdjustment godoc
+// @Summary Records a deduction or advance
+// @Description This endpoint lets the employer or an admin take a deduction or an advance already paid off the net pay of an unpaid month
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param period path string true "Period, such as 2026-10"
+// @Param adjustment body models.SalaryAdjustmentRequest true "Adjustment"
+// @Success 200 {object} models.SalaryRecord
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/payroll/{period}/adjustments [post]
+func AddSalaryAdjustment(c *gin.Context) {
+	var request models.SalaryAdjustmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiring, ok := findPayrollHiring(c)
+	if !ok {
+		return
+	}
+	_, userType, _ := currentUser(c)
+
+	record, err := payroll.AddAdjustment(context.Background(), hiring, c.Param("period"), models.SalaryAdjustment{
+		ID:          primitive.NewObjectID(),
+		Type:        request.Type,
+		Description: request.Description,
+		Amount:      request.Amount,
+		CreatedBy:   userType,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		payrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// DeleteSalaryAdjustment godoc
+// @Summary Removes a deduction or advance
+// @Description This endpoint lets the employer or an admin remove a deduction or advance from an unpaid month
+// @Tags payroll
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param period path string true "Period, such as 2026-10"
+// @Param adjustment_id path string true "Adjustment ID"
+// @Success 200 {object} models.SalaryRecord
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/payroll/{period}/adjustments/{adjustment_id} [delete]
+func DeleteSalaryAdjustment(c *gin.Context) {
+	adjustmentID, err := primitive.ObjectIDFromHex(c.Param("adjustment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid adjustment ID"})
+		return
+	}
+
+	hiring, ok := findPayrollHiring(c)
+	if !ok {
+		return
+	}
+
+	record, err := payroll.RemoveAdjustment(context.Background(), hiring, c.Param("period"), adjustmentID)
+	if err != nil {
+		payrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// MarkSalaryPaid godoc
+// @Summary Marks a salary paid
+// @Description This endpoint lets the employer or an admin record that the salary of a month was paid. The housekeeper is notified.
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param period path string true "Period, such as 2026-10"
+// @Param payment body models.SalaryPaymentRequest false "Payment"
+// @Success 200 {object} models.SalaryRecord
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/payroll/{period}/pay [post]
+func MarkSalaryPaid(c *gin.Context) {
+	var request models.SalaryPaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	hiring, ok := findPayrollHiring(c)
+	if !ok {
+		return
+	}
+	_, userType, _ := currentUser(c)
+
+	record, err := payroll.MarkPaid(context.Background(), hiring.ID, c.Param("period"), userType, request.Note)
+	if err != nil {
+		payrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// DownloadPayslip godoc
+// @Summary Downloads a payslip
+// @Description This endpoint renders the payslip of a month as a PDF, showing gross pay, income tax, pension, deductions, advances and net pay
+// @Tags payroll
+// @Produce application/pdf
+// @Param id path string true "Hiring ID"
+// @Param period path string true "Period, such as 2026-10"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/payroll/{period}/payslip [get]
+func DownloadPayslip(c *gin.Context) {
+	hiring, _, _, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+
+	record, err := payroll.Find(context.Background(), hiring.ID, c.Param("period"))
+	if err != nil {
+		payrollError(c, err)
+		return
+	}
+
+	var employer models.Employer
+	if err := config.DB.Collection("employers").FindOne(context.Background(), bson.M{"_id": hiring.EmployerID}).Decode(&employer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch employer"})
+		return
+	}
+	var housekeeper models.Housekeeper
+	if err := config.DB.Collection("housekeepers").FindOne(context.Background(), bson.M{"_id": hiring.HousekeeperID}).Decode(&housekeeper); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch housekeeper"})
+		return
+	}
+
+	filename := "payslip-" + record.Period + "-" + hiring.ID.Hex() + ".pdf"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", payroll.Payslip(record, employer, housekeeper))
+}
+
+// GetSalaryRecords godoc
+// @Summary Lists salary records
+// @Description This endpoint lets staff follow up on salaries across placements, such as every unpaid salary of a month
+// @Tags admin
+// @Produce json
+// @Param status query string false "Salary status (UNPAID or PAID)"
+// @Param period query string false "Period, such as 2026-10"
+// @Success 200 {array} models.SalaryRecord
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/payroll [get]
+func GetSalaryRecords(c *gin.Context) {
+	filter := bson.M{}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if period := c.Query("period"); period != "" {
+		filter["period"] = period
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "period", Value: -1}, {Key: "created_at", Value: 1}}).SetLimit(500)
+	cursor, err := config.DB.Collection("salary_records").Find(context.Background(), filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch salary records"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	records := []models.SalaryRecord{}
+	if err := cursor.All(context.Background(), &records); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode salary records"})
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// findPayrollHiring loads the hiring in the path for a payroll change, which
+// only its employer and admins may make
+func findPayrollHiring(c *gin.Context) (models.Hiring, bool) {
+	hiring, _, userType, ok := findPartyHiring(c, true)
+	if !ok {
+		return hiring, false
+	}
+	if userType == "housekeeper" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the employer can manage payroll"})
+		return hiring, false
+	}
+	return hiring, true
+}
+
+func payrollError(c *gin.Context, err error) {
+	switch err {
+	case payroll.ErrPeriodNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payroll period not found"})
+	case payroll.ErrAdjustmentNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Adjustment not found"})
+	case payroll.ErrNotPlaced:
+		c.JSON(http.StatusConflict, gin.H{"error": "Payroll is only kept for approved monthly placements"})
+	case payroll.ErrPeriodOutOfRange:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The period must fall within the placement and not after this month"})
+	case payroll.ErrPeriodOpen:
+		c.JSON(http.StatusConflict, gin.H{"error": "This payroll period is already open"})
+	case payroll.ErrAlreadyPaid:
+		c.JSON(http.StatusConflict, gin.H{"error": "This salary is already paid"})
+	case payroll.ErrExceedsPay:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Deductions and advances cannot exceed the pay of the month"})
+	case payroll.ErrPayrollConflict:
+		c.JSON(http.StatusConflict, gin.H{"error": "The salary record was updated in the meantime, please try again"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payroll"})
+	}
+}
@@ -2,100 +2,150 @@ package controllers
 
 import (
 	"backend/config"
+	"backend/events"
 	"backend/models"
+	"backend/moderation"
+	"backend/ratings"
 	"context"
+	"errors"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var errAlreadyReplied = errors.New("review already has a reply")
+
 // CreateReview godoc
 // @Summary Creates a new review for a housekeeper
-// @Description This endpoint creates a new review for a specific housekeeper
+// @Description This endpoint lets the authenticated employer review the housekeeper of one of their completed hirings, once per hiring
 // @Tags review
 // @Accept json
 // @Produce json
-// @Param review body models.Review true "Review data"
+// @Param review body models.ReviewInput true "Review data"
 // @Success 201 {object} models.ReviewResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /create-review [post]
 func CreateReview(c *gin.Context) {
-	var review models.Review
-	if err := c.ShouldBindJSON(&review); err != nil {
+	var input models.ReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	review.CreatedAt = time.Now()
-
-	session, err := config.DB.Client().StartSession()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
+	employerID, userType, ok := currentUser(c)
+	if !ok || userType != "employer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only employers can review housekeepers"})
 		return
 	}
-	defer session.EndSession(context.Background())
 
-	err = session.StartTransaction()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+	hiring, ok := reviewableHiring(c, input.HiringID, employerID, userType)
+	if !ok {
 		return
 	}
-
-	result, err := config.DB.Collection("reviews").InsertOne(context.Background(), review)
-	if err != nil {
-		session.AbortTransaction(context.Background())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review"})
+	if !input.HousekeeperID.IsZero() && input.HousekeeperID != hiring.HousekeeperID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Housekeeper does not match the hiring"})
 		return
 	}
 
-	pipeline := []bson.M{
-		{"$match": bson.M{"housekeeper_id": review.HousekeeperID}},
-		{"$group": bson.M{
-			"_id":           nil,
-			"averageRating": bson.M{"$avg": "$rating"},
-		}},
+	review := models.Review{
+		HiringID:      hiring.ID,
+		EmployerID:    employerID,
+		HousekeeperID: hiring.HousekeeperID,
+		Rating:        input.Rating,
+		Scores:        input.Scores,
+		Comment:       input.Comment,
+		Verified:      true,
+		CreatedAt:     time.Now(),
 	}
+	applyModeration(&review)
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("reviews").InsertOne(uow.Context(), review)
+		if err != nil {
+			return err
+		}
 
-	cursor, err := config.DB.Collection("reviews").Aggregate(context.Background(), pipeline)
+		review.ID = result.InsertedID.(primitive.ObjectID)
+		return uow.Publish(events.ReviewCreated{Review: review})
+	})
 	if err != nil {
-		session.AbortTransaction(context.Background())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate average rating"})
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "This hiring has already been reviewed"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review"})
+		}
 		return
 	}
 
-	var results []bson.M
-	if err = cursor.All(context.Background(), &results); err != nil {
-		session.AbortTransaction(context.Background())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process average rating"})
-		return
+	c.JSON(http.StatusCreated, gin.H{"id": review.ID})
+}
+
+// applyModeration runs the comment of a review through the moderation filter
+func applyModeration(review *models.Review) {
+	review.Status, review.ModerationFlags = moderationStatus(review.Comment, review.Status)
+}
+
+// moderationStatus runs user-written text through the moderation filter and
+// returns the status it should get and the flags raised. Flagged text waits
+// for an admin before it is shown or counted; text an admin already hid stays
+// hidden.
+func moderationStatus(text string, current models.ReviewStatus) (models.ReviewStatus, []string) {
+	result := moderation.Check(text)
+	switch {
+	case current == models.ReviewHidden:
+		return current, result.Flags
+	case result.Flagged():
+		return models.ReviewPendingReview, result.Flags
+	default:
+		return models.ReviewVisible, result.Flags
 	}
+}
 
-	if len(results) > 0 {
-		averageRating := results[0]["averageRating"].(float64)
-		_, err = config.DB.Collection("housekeepers").UpdateOne(context.Background(), bson.M{"_id": review.HousekeeperID}, bson.M{"$set": bson.M{"rating": averageRating}})
-		if err != nil {
-			session.AbortTransaction(context.Background())
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update housekeeper rating"})
-			return
+// reviewableHiring loads the hiring a review refers to and checks that the
+// user was a party to it and that it is completed. It writes the error
+// response itself when the check fails.
+func reviewableHiring(c *gin.Context, hiringID, userID primitive.ObjectID, userType string) (models.Hiring, bool) {
+	var hiring models.Hiring
+	err := config.DB.Collection("hirings").FindOne(context.Background(), bson.M{"_id": hiringID}).Decode(&hiring)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Hiring not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hiring"})
 		}
+		return hiring, false
+	}
 
+	party := hiring.EmployerID
+	if userType == "housekeeper" {
+		party = hiring.HousekeeperID
+	}
+	if party != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only review your own hirings"})
+		return hiring, false
 	}
 
-	if err = session.CommitTransaction(context.Background()); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
+	if hiring.Status != models.Completed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only completed hirings can be reviewed"})
+		return hiring, false
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"id": result.InsertedID})
+	return hiring, true
 }
 
 // GetHousekeeperReviews godoc
 // @Summary Fetches all reviews for a specific housekeeper
-// @Description This endpoint retrieves all reviews associated with a specific housekeeper based on their ID
+// @Description This endpoint retrieves the visible reviews associated with a specific housekeeper based on their ID
 // @Tags review
 // @Accept json
 // @Produce json
@@ -113,7 +163,10 @@ func GetHousekeeperReviews(c *gin.Context) {
 
 	cursor, err := config.DB.Collection("reviews").Find(
 		context.Background(),
-		bson.M{"housekeeper_id": housekeeperID},
+		bson.M{
+			"housekeeper_id": housekeeperID,
+			"status":         bson.M{"$nin": models.HiddenReviewStatuses},
+		},
 	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
@@ -126,6 +179,556 @@ func GetHousekeeperReviews(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode reviews"})
 		return
 	}
+	for i := range reviews {
+		if reviews[i].Reply != nil && reviews[i].Reply.IsHidden() {
+			reviews[i].Reply = nil
+		}
+	}
 
 	c.JSON(http.StatusOK, reviews)
 }
+
+// GetHousekeeperRatingSummary godoc
+// @Summary Fetches the rating breakdown of a housekeeper
+// @Description This endpoint retrieves a housekeeper's average rating, per-dimension averages and star distribution
+// @Tags review
+// @Produce json
+// @Param id path string true "Housekeeper ID"
+// @Success 200 {object} models.RatingSummaryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ratings/housekeeper/{id}/summary [get]
+func GetHousekeeperRatingSummary(c *gin.Context) {
+	housekeeperID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid housekeeper ID"})
+		return
+	}
+
+	var housekeeper models.Housekeeper
+	err = config.DB.Collection("housekeepers").FindOne(context.Background(), bson.M{"_id": housekeeperID}).Decode(&housekeeper)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Housekeeper not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while fetching housekeeper"})
+		}
+		return
+	}
+
+	summary := ratings.EmptySummary()
+	if housekeeper.RatingSummary != nil {
+		summary = *housekeeper.RatingSummary
+	}
+
+	c.JSON(http.StatusOK, models.RatingSummaryResponse{
+		HousekeeperID: housekeeperID.Hex(),
+		RatingSummary: summary,
+	})
+}
+
+// CreateEmployerReview godoc
+// @Summary Creates a new review for an employer
+// @Description This endpoint lets the authenticated housekeeper review the employer of one of their completed hirings, once per hiring. Reviews flagged by the moderation filter wait for an admin before they are shown or counted.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param review body models.EmployerReviewInput true "Review data"
+// @Success 201 {object} models.ReviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ratings/employer [post]
+func CreateEmployerReview(c *gin.Context) {
+	var input models.EmployerReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	housekeeperID, userType, ok := currentUser(c)
+	if !ok || userType != "housekeeper" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only housekeepers can review employers"})
+		return
+	}
+
+	hiring, ok := reviewableHiring(c, input.HiringID, housekeeperID, userType)
+	if !ok {
+		return
+	}
+	if !input.EmployerID.IsZero() && input.EmployerID != hiring.EmployerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Employer does not match the hiring"})
+		return
+	}
+
+	review := models.EmployerReview{
+		HiringID:      hiring.ID,
+		EmployerID:    hiring.EmployerID,
+		HousekeeperID: housekeeperID,
+		Rating:        input.Rating,
+		Comment:       input.Comment,
+		Verified:      true,
+		CreatedAt:     time.Now(),
+	}
+	review.Status, review.ModerationFlags = moderationStatus(review.Comment, "")
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("employer_reviews").InsertOne(uow.Context(), review)
+		if err != nil {
+			return err
+		}
+
+		review.ID = result.InsertedID.(primitive.ObjectID)
+		return uow.Publish(events.EmployerReviewCreated{Review: review})
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "This hiring has already been reviewed"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": review.ID})
+}
+
+// GetEmployerReviews godoc
+// @Summary Fetches the rating and reviews of an employer
+// @Description This endpoint retrieves an employer's aggregate rating and the reviews housekeepers left for them
+// @Tags review
+// @Produce json
+// @Param id path string true "Employer ID"
+// @Success 200 {object} models.EmployerReviewsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ratings/employer/{id} [get]
+func GetEmployerReviews(c *gin.Context) {
+	employerID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employer ID"})
+		return
+	}
+
+	var employer models.Employer
+	err = config.DB.Collection("employers").FindOne(context.Background(), bson.M{"_id": employerID}).Decode(&employer)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Employer not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while fetching employer"})
+		}
+		return
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := config.DB.Collection("employer_reviews").Find(context.Background(), bson.M{
+		"employer_id": employerID,
+		"status":      bson.M{"$nin": models.HiddenReviewStatuses},
+	}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	reviews := []models.EmployerReview{}
+	if err = cursor.All(context.Background(), &reviews); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode reviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.EmployerReviewsResponse{
+		EmployerID: employerID.Hex(),
+		Rating:     employer.Rating,
+		Count:      employer.RatingCount,
+		Reviews:    reviews,
+	})
+}
+
+// reviewEditWindow is how long after posting a review its author may still
+// edit or delete it, configured with REVIEW_EDIT_WINDOW_HOURS
+func reviewEditWindow() time.Duration {
+	if hours, err := strconv.Atoi(os.Getenv("REVIEW_EDIT_WINDOW_HOURS")); err == nil && hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return 72 * time.Hour
+}
+
+// findReview loads the review in the id path parameter, writing the error
+// response itself when it cannot
+func findReview(c *gin.Context) (models.Review, bool) {
+	var review models.Review
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return review, false
+	}
+
+	err = config.DB.Collection("reviews").FindOne(context.Background(), bson.M{"_id": id}).Decode(&review)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review"})
+		}
+		return review, false
+	}
+
+	return review, true
+}
+
+// editableReview loads a review and checks that the authenticated employer
+// wrote it and is still within the edit window
+func editableReview(c *gin.Context) (models.Review, bool) {
+	review, ok := findReview(c)
+	if !ok {
+		return review, false
+	}
+
+	userID, userType, ok := currentUser(c)
+	if !ok || userType != "employer" || review.EmployerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own reviews"})
+		return review, false
+	}
+
+	if time.Since(review.CreatedAt) > reviewEditWindow() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "The review can no longer be changed"})
+		return review, false
+	}
+
+	return review, true
+}
+
+// findEmployerReview loads the employer review in the id path parameter,
+// writing the error response itself when it cannot
+func findEmployerReview(c *gin.Context) (models.EmployerReview, bool) {
+	var review models.EmployerReview
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return review, false
+	}
+
+	err = config.DB.Collection("employer_reviews").FindOne(context.Background(), bson.M{"_id": id}).Decode(&review)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review"})
+		}
+		return review, false
+	}
+
+	return review, true
+}
+
+// editableEmployerReview loads an employer review and checks that the
+// authenticated housekeeper wrote it and is still within the edit window
+func editableEmployerReview(c *gin.Context) (models.EmployerReview, bool) {
+	review, ok := findEmployerReview(c)
+	if !ok {
+		return review, false
+	}
+
+	userID, userType, ok := currentUser(c)
+	if !ok || userType != "housekeeper" || review.HousekeeperID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own reviews"})
+		return review, false
+	}
+
+	if time.Since(review.CreatedAt) > reviewEditWindow() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "The review can no longer be changed"})
+		return review, false
+	}
+
+	return review, true
+}
+
+// UpdateReview godoc
+// @Summary Edits a review
+// @Description This endpoint lets the author of a review change its rating, scores or comment within the edit window. Housekeepers edit their reviews of employers, which have no scores. The previous version is kept in the edit history.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param updates body models.ReviewUpdate true "Updated review data"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ratings/{id} [put]
+func UpdateReview(c *gin.Context) {
+	var updates models.ReviewUpdate
+	if err := c.ShouldBindJSON(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, userType, _ := currentUser(c); userType == "housekeeper" {
+		updateEmployerReview(c, updates)
+		return
+	}
+
+	review, ok := editableReview(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	previousStatus := review.Status
+	previous := models.ReviewRevision{
+		Rating:   review.Rating,
+		Scores:   review.Scores,
+		Comment:  review.Comment,
+		EditedAt: now,
+	}
+
+	if updates.Rating != 0 {
+		review.Rating = updates.Rating
+	}
+	if updates.Scores != nil {
+		review.Scores = updates.Scores
+	}
+	if updates.Comment != nil {
+		review.Comment = *updates.Comment
+	}
+	review.EditHistory = append(review.EditHistory, previous)
+	review.UpdatedAt = now
+	applyModeration(&review)
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		_, err := config.DB.Collection("reviews").UpdateOne(uow.Context(),
+			bson.M{"_id": review.ID},
+			bson.M{
+				"$set": bson.M{
+					"rating":           review.Rating,
+					"scores":           review.Scores,
+					"comment":          review.Comment,
+					"status":           review.Status,
+					"moderation_flags": review.ModerationFlags,
+					"updated_at":       review.UpdatedAt,
+				},
+				"$push": bson.M{"edit_history": previous},
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		return uow.Publish(events.ReviewUpdated{Review: review, Previous: previous, PreviousStatus: previousStatus})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update review"})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// updateEmployerReview applies an edit to the housekeeper's review of an
+// employer
+func updateEmployerReview(c *gin.Context, updates models.ReviewUpdate) {
+	if updates.Scores != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reviews of employers have no scores"})
+		return
+	}
+
+	review, ok := editableEmployerReview(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	previousStatus := review.Status
+	previous := models.ReviewRevision{
+		Rating:   review.Rating,
+		Comment:  review.Comment,
+		EditedAt: now,
+	}
+
+	if updates.Rating != 0 {
+		review.Rating = updates.Rating
+	}
+	if updates.Comment != nil {
+		review.Comment = *updates.Comment
+	}
+	review.EditHistory = append(review.EditHistory, previous)
+	review.UpdatedAt = now
+	review.Status, review.ModerationFlags = moderationStatus(review.Comment, review.Status)
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		_, err := config.DB.Collection("employer_reviews").UpdateOne(uow.Context(),
+			bson.M{"_id": review.ID},
+			bson.M{
+				"$set": bson.M{
+					"rating":           review.Rating,
+					"comment":          review.Comment,
+					"status":           review.Status,
+					"moderation_flags": review.ModerationFlags,
+					"updated_at":       review.UpdatedAt,
+				},
+				"$push": bson.M{"edit_history": previous},
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		return uow.Publish(events.EmployerReviewUpdated{Review: review, Previous: previous, PreviousStatus: previousStatus})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update review"})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// DeleteReview godoc
+// @Summary Deletes a review
+// @Description This endpoint lets the author of a review, or of a housekeeper's review of an employer, delete it within the edit window
+// @Tags review
+// @Produce json
+// @Param id path string true "Review ID"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ratings/{id} [delete]
+func DeleteReview(c *gin.Context) {
+	if _, userType, _ := currentUser(c); userType == "housekeeper" {
+		deleteEmployerReview(c)
+		return
+	}
+
+	review, ok := editableReview(c)
+	if !ok {
+		return
+	}
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("reviews").DeleteOne(uow.Context(), bson.M{"_id": review.ID})
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount == 0 {
+			return mongo.ErrNoDocuments
+		}
+
+		return uow.Publish(events.ReviewDeleted{Review: review})
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
+}
+
+// deleteEmployerReview deletes the housekeeper's review of an employer
+func deleteEmployerReview(c *gin.Context) {
+	review, ok := editableEmployerReview(c)
+	if !ok {
+		return
+	}
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("employer_reviews").DeleteOne(uow.Context(), bson.M{"_id": review.ID})
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount == 0 {
+			return mongo.ErrNoDocuments
+		}
+
+		return uow.Publish(events.EmployerReviewDeleted{Review: review})
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
+}
+
+// ReplyToReview godoc
+// @Summary Replies to a review
+// @Description This endpoint lets the reviewed housekeeper post a single public reply to a review. Replies flagged by the moderation filter are shown once an admin approves them.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param reply body models.ReviewReply true "Reply"
+// @Success 201 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ratings/{id}/reply [post]
+func ReplyToReview(c *gin.Context) {
+	var reply models.ReviewReply
+	if err := c.ShouldBindJSON(&reply); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, ok := findReview(c)
+	if !ok {
+		return
+	}
+
+	userID, userType, ok := currentUser(c)
+	if !ok || userType != "housekeeper" || review.HousekeeperID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the reviewed housekeeper can reply"})
+		return
+	}
+
+	reply.Status, reply.ModerationFlags = moderationStatus(reply.Comment, "")
+	reply.ModerationNote = ""
+	reply.ModeratedAt = time.Time{}
+	reply.CreatedAt = time.Now()
+
+	err := events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		// Matching on a missing reply keeps it to a single reply even under concurrent requests
+		result, err := config.DB.Collection("reviews").UpdateOne(uow.Context(),
+			bson.M{"_id": review.ID, "reply": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"reply": reply}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errAlreadyReplied
+		}
+
+		review.Reply = &reply
+		return uow.Publish(events.ReviewReplied{Review: review})
+	})
+	if err != nil {
+		if err == errAlreadyReplied {
+			c.JSON(http.StatusConflict, gin.H{"error": "The review already has a reply"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reply to review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Reply posted successfully"})
+}
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"backend/availability"
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RequestReplacement godoc
+// @Summary Requests a replacement housekeeper
+// @Description This endpoint lets the employer, or an admin on their behalf, end a placement during its trial period. The placement is terminated and a pending replacement hiring with the same terms is sent to the chosen housekeeper, linked to the original through replacement_for.
+// @Tags hiring
+// @Accept json
+// @Produce json
+// @Param id path string true "Hiring ID"
+// @Param replacement body models.ReplacementRequest true "Replacement request"
+// @Success 201 {object} models.Hiring
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /hiring/{id}/replacement [post]
+func RequestReplacement(c *gin.Context) {
+	var request models.ReplacementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiring, _, userType, ok := findPartyHiring(c, true)
+	if !ok {
+		return
+	}
+	if userType == "housekeeper" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the employer can request a replacement"})
+		return
+	}
+
+	if !isReplacementReason(request.Reason) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid replacement reason"})
+		return
+	}
+	if request.Reason == models.OtherReplacement && strings.TrimSpace(request.Note) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A note is required when the reason is OTHER"})
+		return
+	}
+
+	now := time.Now()
+	if hiring.Status != models.Approved || hiring.Trial == nil || hiring.Trial.Status != models.TrialActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "Replacements can only be requested for placements in their trial period"})
+		return
+	}
+	if !now.Before(hiring.Trial.EndsAt) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The trial period has ended"})
+		return
+	}
+
+	startDate := request.StartDate
+	if startDate.IsZero() {
+		startDate = now
+	} else if startDate.Before(now.Truncate(24 * time.Hour)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The start date must not be in the past"})
+		return
+	}
+
+	if request.HousekeeperID == hiring.HousekeeperID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Choose a different housekeeper for the replacement"})
+		return
+	}
+
+	var housekeeper models.Housekeeper
+	err := config.DB.Collection("housekeepers").FindOne(context.Background(), bson.M{"_id": request.HousekeeperID}).Decode(&housekeeper)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Housekeeper not found"})
+		return
+	}
+
+	var employer models.Employer
+	err = config.DB.Collection("employers").FindOne(context.Background(), bson.M{"_id": hiring.EmployerID}).Decode(&employer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load employer"})
+		return
+	}
+	employer.Password = ""
+	housekeeper.Password = ""
+
+	replacement := models.Hiring{
+		EmployerID:      hiring.EmployerID,
+		HousekeeperID:   housekeeper.ID,
+		Status:          models.Pending,
+		Requirements:    hiring.Requirements,
+		SalaryOffer:     hiring.SalaryOffer,
+		StartDate:       startDate,
+		EndDate:         hiring.EndDate,
+		EmploymentType:  hiring.EmploymentType,
+		DeliveryType:    hiring.DeliveryType,
+		BookingMode:     hiring.BookingMode,
+		PensionEnrolled: hiring.PensionEnrolled,
+		ReplacementFor:  hiring.ID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	conflicts, err := availability.Conflicts(context.Background(), replacement)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check availability"})
+		return
+	}
+	if conflicts {
+		c.JSON(http.StatusConflict, gin.H{"error": "The housekeeper already has a placement or visits on these dates"})
+		return
+	}
+
+	err = events.RunInTransaction(context.Background(), func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("hirings").InsertOne(uow.Context(), replacement)
+		if err != nil {
+			return err
+		}
+		replacement.ID = result.InsertedID.(primitive.ObjectID)
+
+		trial := *hiring.Trial
+		trial.Status = models.TrialReplaced
+		trial.Replacement = &models.Replacement{
+			Reason:      request.Reason,
+			Note:        strings.TrimSpace(request.Note),
+			HiringID:    replacement.ID,
+			RequestedBy: userType,
+			RequestedAt: now,
+		}
+
+		// Matching on the trial status keeps two replacement requests from
+		// both going through
+		update, err := config.DB.Collection("hirings").UpdateOne(uow.Context(),
+			bson.M{"_id": hiring.ID, "status": models.Approved, "trial.status": models.TrialActive},
+			bson.M{"$set": bson.M{
+				"status":    models.Terminated,
+				"trial":     trial,
+				"ended_at":  now,
+				"update_at": now,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if update.MatchedCount == 0 {
+			return errHiringChanged
+		}
+
+		original := hiring
+		original.Status = models.Terminated
+		original.EndedAt = now
+		original.Trial = &trial
+		original.UpdatedAt = now
+		if err := uow.Publish(events.HiringStatusChanged{Hiring: original, PreviousStatus: models.Approved}); err != nil {
+			return err
+		}
+
+		return uow.Publish(events.HiringCreated{Hiring: replacement, Employer: employer, Housekeeper: housekeeper})
+	})
+	if err != nil {
+		if err == errHiringChanged {
+			c.JSON(http.StatusConflict, gin.H{"error": "The hiring was updated in the meantime, please try again"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request replacement"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, replacement)
+}
+
+func isReplacementReason(reason models.ReplacementReason) bool {
+	for _, allowed := range models.ReplacementReasons {
+		if reason == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// newTrial opens the trial period of a placement approved now that starts on
+// startDate
+func newTrial(startDate, now time.Time) models.Trial {
+	if startDate.Before(now) {
+		startDate = now
+	}
+	return models.Trial{
+		Status:   models.TrialActive,
+		StartsAt: startDate,
+		EndsAt:   startDate.Add(trialPeriod()),
+	}
+}
+
+// trialPeriod is how long the trial of a placement lasts, configured with
+// HIRING_TRIAL_DAYS
+func trialPeriod() time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("HIRING_TRIAL_DAYS")); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return 30 * 24 * time.Hour
+}
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/webhooks"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateWebhook godoc
+// @Summary Creates a webhook subscription
+// @Description This endpoint subscribes a partner URL to hiring, review and housekeeper events. The signing secret is only returned once.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param subscription body models.WebhookSubscription true "Webhook subscription data"
+// @Success 201 {object} models.WebhookSubscription
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks [post]
+func CreateWebhook(c *gin.Context) {
+	var subscription models.WebhookSubscription
+	if err := c.ShouldBindJSON(&subscription); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, eventType := range subscription.EventTypes {
+		if !webhooks.IsEventType(eventType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown event type: " + eventType})
+			return
+		}
+	}
+
+	if subscription.Secret == "" {
+		secret, err := webhooks.GenerateSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate webhook secret"})
+			return
+		}
+		subscription.Secret = secret
+	}
+
+	subscription.ID = primitive.NewObjectID()
+	subscription.Active = true
+	subscription.CreatedAt = time.Now()
+	subscription.UpdatedAt = time.Now()
+
+	if _, err := config.DB.Collection("webhook_subscriptions").InsertOne(context.Background(), subscription); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook subscription"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// GetWebhooks godoc
+// @Summary Lists webhook subscriptions
+// @Description This endpoint retrieves all webhook subscriptions without their secrets
+// @Tags webhook
+// @Produce json
+// @Success 200 {array} models.WebhookSubscription
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks [get]
+func GetWebhooks(c *gin.Context) {
+	cursor, err := config.DB.Collection("webhook_subscriptions").Find(
+		context.Background(),
+		bson.M{},
+		options.Find().SetProjection(bson.M{"secret": 0}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook subscriptions"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	subscriptions := []models.WebhookSubscription{}
+	if err := cursor.All(context.Background(), &subscriptions); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode webhook subscriptions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// UpdateWebhook godoc
+// @Summary Updates a webhook subscription
+// @Description This endpoint changes the URL, event types, description or active flag of a webhook subscription
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook subscription ID"
+// @Param updates body models.WebhookSubscriptionUpdate true "Updated subscription data"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks/{id} [put]
+func UpdateWebhook(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	var updates models.WebhookSubscriptionUpdate
+	if err := c.ShouldBindJSON(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setDoc := bson.M{"updated_at": time.Now()}
+	if updates.URL != "" {
+		setDoc["url"] = updates.URL
+	}
+	if updates.EventTypes != nil {
+		for _, eventType := range updates.EventTypes {
+			if !webhooks.IsEventType(eventType) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown event type: " + eventType})
+				return
+			}
+		}
+		setDoc["event_types"] = updates.EventTypes
+	}
+	if updates.Description != "" {
+		setDoc["description"] = updates.Description
+	}
+	if updates.Active != nil {
+		setDoc["active"] = *updates.Active
+	}
+
+	result, err := config.DB.Collection("webhook_subscriptions").UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": setDoc},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook subscription"})
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook subscription not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook subscription updated successfully"})
+}
+
+// DeleteWebhook godoc
+// @Summary Deletes a webhook subscription
+// @Description This endpoint removes a webhook subscription; its delivery log is kept
+// @Tags webhook
+// @Produce json
+// @Param id path string true "Webhook subscription ID"
+// @Success 200 {object} models.GenericResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks/{id} [delete]
+func DeleteWebhook(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	result, err := config.DB.Collection("webhook_subscriptions").DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook subscription"})
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook subscription not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook subscription deleted successfully"})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Lists the deliveries of a webhook subscription
+// @Description This endpoint retrieves the most recent deliveries of a webhook subscription, newest first
+// @Tags webhook
+// @Produce json
+// @Param id path string true "Webhook subscription ID"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks/{id}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	err = config.DB.Collection("webhook_subscriptions").FindOne(context.Background(), bson.M{"_id": id}).Err()
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook subscription not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook subscription"})
+		}
+		return
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(100)
+	cursor, err := config.DB.Collection("webhook_deliveries").Find(context.Background(), bson.M{"subscription_id": id}, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook deliveries"})
+		return
+	}
+	defer cursor.Close(context.Background())
+
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(context.Background(), &deliveries); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode webhook deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,47 @@
+package deliveries
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OpenStatuses are the statuses of deliveries that have not finished yet
+var OpenStatuses = []models.DeliveryStatus{models.DeliveryScheduled, models.DeliveryEnRoute}
+
+// Next returns the status a delivery in status moves to, or an empty status
+// when it cannot move forward
+func Next(status models.DeliveryStatus) models.DeliveryStatus {
+	switch status {
+	case models.DeliveryScheduled:
+		return models.DeliveryEnRoute
+	case models.DeliveryEnRoute:
+		return models.DeliveryDelivered
+	default:
+		return ""
+	}
+}
+
+// Cancel cancels the open delivery of a hiring, for when the hiring ends
+// before the housekeeper is handed over
+func Cancel(ctx context.Context, hiringID primitive.ObjectID) error {
+	_, err := config.DB.Collection("deliveries").UpdateOne(ctx,
+		bson.M{"hiring_id": hiringID, "status": bson.M{"$in": OpenStatuses}},
+		bson.M{"$set": bson.M{"status": models.DeliveryCancelled, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// OfficeAddress is where housekeepers are picked up by default, configured
+// with AGAZH_OFFICE_ADDRESS
+func OfficeAddress() string {
+	if address := os.Getenv("AGAZH_OFFICE_ADDRESS"); address != "" {
+		return address
+	}
+	return "AGAZH office"
+}
@@ -0,0 +1,153 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Event is a domain fact published after a successful write
+type Event interface {
+	EventName() string
+}
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, event Event) error
+
+type subscription struct {
+	name    string
+	handler Handler
+	async   bool
+}
+
+// Bus dispatches events to subscribers in process. Synchronous subscribers
+// run inside the publishing transaction, so their writes commit or roll back
+// together with the handler's. Asynchronous subscribers are fed by the outbox
+// relay once the transaction has committed.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription
+}
+
+func NewBus() *Bus {
+	return &Bus{subscriptions: map[string][]subscription{}}
+}
+
+// Default is the bus used by the controllers
+var Default = NewBus()
+
+func (b *Bus) subscribe(eventName, name string, handler Handler, async bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, sub := range b.subscriptions[eventName] {
+		if sub.name == name {
+			panic(fmt.Sprintf("events: %s already has a subscriber named %s", eventName, name))
+		}
+	}
+
+	b.subscriptions[eventName] = append(b.subscriptions[eventName], subscription{
+		name:    name,
+		handler: handler,
+		async:   async,
+	})
+}
+
+func (b *Bus) subscribers(eventName string, async bool) []subscription {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var subs []subscription
+	for _, sub := range b.subscriptions[eventName] {
+		if sub.async == async {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// dispatchSync runs the synchronous subscribers of event, stopping at the
+// first failure
+func (b *Bus) dispatchSync(ctx context.Context, event Event) error {
+	for _, sub := range b.subscribers(event.EventName(), false) {
+		if err := sub.handler(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", sub.name, err)
+		}
+	}
+	return nil
+}
+
+// dispatchAsync runs the asynchronous subscribers of event that are not in
+// done and returns the names of those that succeeded
+func (b *Bus) dispatchAsync(ctx context.Context, event Event, done []string) ([]string, error) {
+	var delivered []string
+	var errs []error
+
+	for _, sub := range b.subscribers(event.EventName(), true) {
+		if contains(done, sub.name) {
+			continue
+		}
+
+		if err := sub.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		delivered = append(delivered, sub.name)
+	}
+
+	return delivered, errors.Join(errs...)
+}
+
+// Subscribe registers a handler for events of type E that runs inside the
+// publishing transaction. Subscriber names must be unique per event type.
+func Subscribe[E Event](b *Bus, name string, handler func(ctx context.Context, event E) error) {
+	var zero E
+	b.subscribe(zero.EventName(), name, typed(handler), false)
+}
+
+// SubscribeAsync registers a handler for events of type E that the outbox
+// relay calls after the publishing transaction has committed. Subscriber
+// names must be unique per event type.
+func SubscribeAsync[E Event](b *Bus, name string, handler func(ctx context.Context, event E) error) {
+	var zero E
+	b.subscribe(zero.EventName(), name, typed(handler), true)
+}
+
+func typed[E Event](handler func(ctx context.Context, event E) error) Handler {
+	return func(ctx context.Context, event Event) error {
+		return handler(ctx, event.(E))
+	}
+}
+
+// decoders turn outbox payloads back into typed events
+var decoders = map[string]func(raw bson.Raw) (Event, error){}
+
+// register makes events of type E decodable by the outbox relay
+func register[E Event]() {
+	var zero E
+	decoders[zero.EventName()] = func(raw bson.Raw) (Event, error) {
+		var event E
+		err := bson.Unmarshal(raw, &event)
+		return event, err
+	}
+}
+
+func decode(name string, raw bson.Raw) (Event, error) {
+	decoder, ok := decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	return decoder(raw)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
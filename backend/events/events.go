@@ -0,0 +1,276 @@
+package events
+
+import "backend/models"
+
+// Event names, shared with webhook event types where they overlap
+const (
+	HiringCreatedName           = "hiring.created"
+	HiringStatusChangedName     = "hiring.status_changed"
+	ReviewCreatedName           = "review.created"
+	ReviewUpdatedName           = "review.updated"
+	ReviewDeletedName           = "review.deleted"
+	ReviewRepliedName           = "review.replied"
+	ReviewModeratedName         = "review.moderated"
+	EmployerReviewCreatedName   = "employer_review.created"
+	EmployerReviewUpdatedName   = "employer_review.updated"
+	EmployerReviewDeletedName   = "employer_review.deleted"
+	EmployerReviewModeratedName = "employer_review.moderated"
+	ReviewReplyModeratedName    = "review.reply_moderated"
+	HousekeeperRegisteredName   = "housekeeper.registered"
+	HousekeeperUpdatedName      = "housekeeper.updated"
+	OfferMadeName               = "offer.made"
+	OfferRespondedName          = "offer.responded"
+	MessageSentName             = "message.sent"
+	InterviewChangedName        = "interview.changed"
+	OccurrenceChangedName       = "occurrence.changed"
+	TrialPassedName             = "hiring.trial_passed"
+	DeliveryChangedName         = "delivery.changed"
+	ContractGeneratedName       = "contract.generated"
+	ContractSignedName          = "contract.signed"
+	PaymentChangedName          = "payment.changed"
+	InvoiceIssuedName           = "invoice.issued"
+	SalaryRecordChangedName     = "salary_record.changed"
+)
+
+// HiringCreated is published when an employer sends a hiring request
+type HiringCreated struct {
+	Hiring      models.Hiring      `json:"hiring" bson:"hiring"`
+	Employer    models.Employer    `json:"employer" bson:"employer"`
+	Housekeeper models.Housekeeper `json:"housekeeper" bson:"housekeeper"`
+}
+
+func (HiringCreated) EventName() string { return HiringCreatedName }
+
+// HiringStatusChanged is published when a hiring request moves to a new status
+type HiringStatusChanged struct {
+	Hiring         models.Hiring       `json:"hiring" bson:"hiring"`
+	PreviousStatus models.HiringStatus `json:"previous_status" bson:"previous_status"`
+}
+
+func (HiringStatusChanged) EventName() string { return HiringStatusChangedName }
+
+// ReviewCreated is published when an employer reviews a housekeeper
+type ReviewCreated struct {
+	Review models.Review `json:"review" bson:"review"`
+}
+
+func (ReviewCreated) EventName() string { return ReviewCreatedName }
+
+// ReviewUpdated is published when an employer edits their review
+type ReviewUpdated struct {
+	Review         models.Review         `json:"review" bson:"review"`
+	Previous       models.ReviewRevision `json:"previous" bson:"previous"`
+	PreviousStatus models.ReviewStatus   `json:"previous_status" bson:"previous_status"`
+}
+
+func (ReviewUpdated) EventName() string { return ReviewUpdatedName }
+
+// ReviewDeleted is published when an employer deletes their review
+type ReviewDeleted struct {
+	Review models.Review `json:"review" bson:"review"`
+}
+
+func (ReviewDeleted) EventName() string { return ReviewDeletedName }
+
+// ReviewReplied is published when a housekeeper replies to a review
+type ReviewReplied struct {
+	Review models.Review `json:"review" bson:"review"`
+}
+
+func (ReviewReplied) EventName() string { return ReviewRepliedName }
+
+// ReviewModerated is published when an admin hides or restores a review
+type ReviewModerated struct {
+	Review         models.Review       `json:"review" bson:"review"`
+	PreviousStatus models.ReviewStatus `json:"previous_status" bson:"previous_status"`
+}
+
+func (ReviewModerated) EventName() string { return ReviewModeratedName }
+
+// EmployerReviewCreated is published when a housekeeper reviews an employer
+type EmployerReviewCreated struct {
+	Review models.EmployerReview `json:"review" bson:"review"`
+}
+
+func (EmployerReviewCreated) EventName() string { return EmployerReviewCreatedName }
+
+// EmployerReviewUpdated is published when a housekeeper edits their review of
+// an employer
+type EmployerReviewUpdated struct {
+	Review         models.EmployerReview `json:"review" bson:"review"`
+	Previous       models.ReviewRevision `json:"previous" bson:"previous"`
+	PreviousStatus models.ReviewStatus   `json:"previous_status" bson:"previous_status"`
+}
+
+func (EmployerReviewUpdated) EventName() string { return EmployerReviewUpdatedName }
+
+// EmployerReviewDeleted is published when a housekeeper deletes their review
+// of an employer
+type EmployerReviewDeleted struct {
+	Review models.EmployerReview `json:"review" bson:"review"`
+}
+
+func (EmployerReviewDeleted) EventName() string { return EmployerReviewDeletedName }
+
+// EmployerReviewModerated is published when an admin hides or restores a
+// review of an employer
+type EmployerReviewModerated struct {
+	Review         models.EmployerReview `json:"review" bson:"review"`
+	PreviousStatus models.ReviewStatus   `json:"previous_status" bson:"previous_status"`
+}
+
+func (EmployerReviewModerated) EventName() string { return EmployerReviewModeratedName }
+
+// ReviewReplyModerated is published when an admin hides or restores the reply
+// to a review
+type ReviewReplyModerated struct {
+	Review         models.Review       `json:"review" bson:"review"`
+	PreviousStatus models.ReviewStatus `json:"previous_status" bson:"previous_status"`
+}
+
+func (ReviewReplyModerated) EventName() string { return ReviewReplyModeratedName }
+
+// HousekeeperRegistered is published when a housekeeper signs up
+type HousekeeperRegistered struct {
+	Housekeeper models.Housekeeper `json:"housekeeper" bson:"housekeeper"`
+}
+
+func (HousekeeperRegistered) EventName() string { return HousekeeperRegisteredName }
+
+// HousekeeperUpdated is published when a housekeeper's profile changes
+type HousekeeperUpdated struct {
+	Housekeeper models.Housekeeper `json:"housekeeper" bson:"housekeeper"`
+}
+
+func (HousekeeperUpdated) EventName() string { return HousekeeperUpdatedName }
+
+// OfferMade is published when either party proposes terms for a hiring
+type OfferMade struct {
+	Offer  models.Offer  `json:"offer" bson:"offer"`
+	Hiring models.Hiring `json:"hiring" bson:"hiring"`
+}
+
+func (OfferMade) EventName() string { return OfferMadeName }
+
+// OfferResponded is published when an offer is accepted or declined
+type OfferResponded struct {
+	Offer  models.Offer  `json:"offer" bson:"offer"`
+	Hiring models.Hiring `json:"hiring" bson:"hiring"`
+}
+
+func (OfferResponded) EventName() string { return OfferRespondedName }
+
+// MessageSent is published when a party sends a message about a hiring
+type MessageSent struct {
+	Message models.Message `json:"message" bson:"message"`
+	Hiring  models.Hiring  `json:"hiring" bson:"hiring"`
+}
+
+func (MessageSent) EventName() string { return MessageSentName }
+
+// InterviewChanged is published when an interview is proposed, rescheduled,
+// confirmed, cancelled or completed. Actor is the party that changed it.
+type InterviewChanged struct {
+	Interview      models.Interview       `json:"interview" bson:"interview"`
+	PreviousStatus models.InterviewStatus `json:"previous_status,omitempty" bson:"previous_status,omitempty"`
+	Actor          string                 `json:"actor" bson:"actor"`
+}
+
+func (InterviewChanged) EventName() string { return InterviewChangedName }
+
+// OccurrenceChanged is published when a visit of an hourly booking is
+// skipped, cancelled or completed
+type OccurrenceChanged struct {
+	Occurrence models.Occurrence `json:"occurrence" bson:"occurrence"`
+}
+
+func (OccurrenceChanged) EventName() string { return OccurrenceChangedName }
+
+// TrialPassed is published when a placement reaches the end of its trial
+// period without a replacement being requested
+type TrialPassed struct {
+	Hiring models.Hiring `json:"hiring" bson:"hiring"`
+}
+
+func (TrialPassed) EventName() string { return TrialPassedName }
+
+// DeliveryChanged is published when staff plan, reschedule or progress the
+// delivery of a housekeeper
+type DeliveryChanged struct {
+	Delivery       models.Delivery       `json:"delivery" bson:"delivery"`
+	PreviousStatus models.DeliveryStatus `json:"previous_status,omitempty" bson:"previous_status,omitempty"`
+}
+
+func (DeliveryChanged) EventName() string { return DeliveryChangedName }
+
+// ContractGenerated is published when a new version of a hiring's contract
+// is ready to be signed. The contract is published without its PDFs.
+type ContractGenerated struct {
+	Contract models.Contract `json:"contract" bson:"contract"`
+}
+
+func (ContractGenerated) EventName() string { return ContractGeneratedName }
+
+// ContractSigned is published when a party signs a contract. The contract is
+// published without its PDFs.
+type ContractSigned struct {
+	Contract models.Contract `json:"contract" bson:"contract"`
+	Party    string          `json:"party" bson:"party"`
+}
+
+func (ContractSigned) EventName() string { return ContractSignedName }
+
+// PaymentChanged is published when a payment is requested, paid, released,
+// refunded, fails or is cancelled. PreviousStatus is empty for a new payment.
+type PaymentChanged struct {
+	Payment        models.Payment       `json:"payment" bson:"payment"`
+	PreviousStatus models.PaymentStatus `json:"previous_status,omitempty" bson:"previous_status,omitempty"`
+}
+
+func (PaymentChanged) EventName() string { return PaymentChangedName }
+
+// InvoiceIssued is published when an employer is invoiced for a placement
+type InvoiceIssued struct {
+	Invoice models.Invoice `json:"invoice" bson:"invoice"`
+}
+
+func (InvoiceIssued) EventName() string { return InvoiceIssuedName }
+
+// SalaryRecordChanged is published when a payroll period is opened, its
+// deductions or advances change, or its salary is paid. PreviousStatus is
+// empty for a new period.
+type SalaryRecordChanged struct {
+	Record         models.SalaryRecord `json:"record" bson:"record"`
+	PreviousStatus models.SalaryStatus `json:"previous_status,omitempty" bson:"previous_status,omitempty"`
+}
+
+func (SalaryRecordChanged) EventName() string { return SalaryRecordChangedName }
+
+func init() {
+	register[HiringCreated]()
+	register[HiringStatusChanged]()
+	register[ReviewCreated]()
+	register[ReviewUpdated]()
+	register[ReviewDeleted]()
+	register[ReviewReplied]()
+	register[ReviewModerated]()
+	register[EmployerReviewCreated]()
+	register[EmployerReviewUpdated]()
+	register[EmployerReviewDeleted]()
+	register[EmployerReviewModerated]()
+	register[ReviewReplyModerated]()
+	register[HousekeeperRegistered]()
+	register[HousekeeperUpdated]()
+	register[OfferMade]()
+	register[OfferResponded]()
+	register[MessageSent]()
+	register[InterviewChanged]()
+	register[OccurrenceChanged]()
+	register[TrialPassed]()
+	register[DeliveryChanged]()
+	register[ContractGenerated]()
+	register[ContractSigned]()
+	register[PaymentChanged]()
+	register[InvoiceIssued]()
+	register[SalaryRecordChanged]()
+}
@@ -0,0 +1,163 @@
+package events
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// relayLease is how long a relay owns a claimed entry before another
+	// instance may take it over
+	relayLease = 2 * time.Minute
+	// relayTimeout bounds the subscribers of one entry, well inside the
+	// lease, so a slow handler cannot outlive the claim and run twice
+	relayTimeout = 90 * time.Second
+
+	relayMaxAttempts  = 10
+	relayInitialDelay = 30 * time.Second
+)
+
+var relayWakeup = make(chan struct{}, 1)
+
+// wakeRelay asks the relay to look at the outbox now instead of waiting for
+// its next poll
+func wakeRelay() {
+	select {
+	case relayWakeup <- struct{}{}:
+	default:
+	}
+}
+
+// StartRelay publishes outbox entries to the asynchronous subscribers of the
+// default bus until ctx is cancelled
+func StartRelay(ctx context.Context, interval time.Duration) {
+	Default.StartRelay(ctx, interval)
+}
+
+func (b *Bus) StartRelay(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.RelayPending(ctx); err != nil {
+			log.Printf("Error relaying outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-relayWakeup:
+		}
+	}
+}
+
+// RelayPending publishes every outbox entry that is due. Each entry is
+// claimed atomically, so concurrent relays never process the same entry, and
+// the subscribers it was delivered to are recorded, so a retry only calls the
+// ones that failed.
+func (b *Bus) RelayPending(ctx context.Context) error {
+	for {
+		entry, err := claim(ctx)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := b.relay(ctx, entry); err != nil {
+			return err
+		}
+	}
+}
+
+func claim(ctx context.Context) (models.OutboxEntry, error) {
+	now := time.Now()
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": models.OutboxPending, "available_at": bson.M{"$lte": now}},
+			// Entries left behind by a relay that crashed mid-way
+			{"status": models.OutboxProcessing, "locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":       models.OutboxProcessing,
+		"locked_until": now.Add(relayLease),
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var entry models.OutboxEntry
+	err := config.DB.Collection("outbox").FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry)
+	return entry, err
+}
+
+func (b *Bus) relay(ctx context.Context, entry models.OutboxEntry) error {
+	collection := config.DB.Collection("outbox")
+
+	// Outcomes are only recorded while this relay still holds the lease; once
+	// it expired, the relay that took the entry over records its own
+	owned := bson.M{"_id": entry.ID, "status": models.OutboxProcessing, "locked_until": entry.LockedUntil}
+
+	event, err := decode(entry.Event, entry.Payload)
+	if err != nil {
+		_, dbErr := collection.UpdateOne(ctx, owned, bson.M{"$set": bson.M{
+			"status":     models.OutboxFailed,
+			"last_error": err.Error(),
+		}})
+		return dbErr
+	}
+
+	handlerCtx, cancel := context.WithTimeout(ctx, relayTimeout)
+	delivered, handlerErr := b.dispatchAsync(handlerCtx, event, entry.DeliveredTo)
+	cancel()
+
+	// Deliveries are recorded whatever happened to the lease, so no relay
+	// calls those subscribers again
+	if len(delivered) > 0 {
+		_, err := collection.UpdateOne(ctx,
+			bson.M{"_id": entry.ID},
+			bson.M{"$addToSet": bson.M{"delivered_to": bson.M{"$each": delivered}}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	var update bson.M
+	if handlerErr == nil {
+		update = bson.M{"$set": bson.M{"status": models.OutboxPublished, "published_at": time.Now(), "last_error": ""}}
+	} else {
+		attempts := entry.Attempts + 1
+		status := models.OutboxPending
+		if attempts >= relayMaxAttempts {
+			status = models.OutboxFailed
+		}
+		log.Printf("Error relaying %s %s: %v", entry.Event, entry.ID.Hex(), handlerErr)
+
+		update = bson.M{"$set": bson.M{
+			"status":       status,
+			"attempts":     attempts,
+			"last_error":   handlerErr.Error(),
+			"available_at": time.Now().Add(relayInitialDelay << (attempts - 1)),
+		}}
+	}
+
+	result, err := collection.UpdateOne(ctx, owned, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		log.Printf("Lease on %s %s expired before its outcome was recorded", entry.Event, entry.ID.Hex())
+	}
+	return nil
+}
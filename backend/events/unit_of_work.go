@@ -0,0 +1,94 @@
+package events
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UnitOfWork groups a handler's writes and the events they produce into one
+// Mongo transaction
+type UnitOfWork struct {
+	ctx    mongo.SessionContext
+	bus    *Bus
+	events []Event
+}
+
+// Context returns the session context. Every write that belongs to the unit
+// of work must use it, otherwise it runs outside the transaction.
+func (u *UnitOfWork) Context() context.Context {
+	return u.ctx
+}
+
+// Publish runs the synchronous subscribers of event inside the transaction
+// and queues the event in the outbox for the asynchronous ones
+func (u *UnitOfWork) Publish(event Event) error {
+	if err := u.bus.dispatchSync(u.ctx, event); err != nil {
+		return err
+	}
+	u.events = append(u.events, event)
+	return nil
+}
+
+// RunInTransaction runs fn in a transaction on the default bus. Outbox entries
+// for the published events are inserted in the same transaction, so they
+// exist if and only if the data was committed. Transactions need MongoDB to
+// run as a replica set.
+func RunInTransaction(ctx context.Context, fn func(uow *UnitOfWork) error) error {
+	return Default.RunInTransaction(ctx, fn)
+}
+
+func (b *Bus) RunInTransaction(ctx context.Context, fn func(uow *UnitOfWork) error) error {
+	session, err := config.DB.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	published := 0
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		// WithTransaction retries fn on transient errors, so start afresh each time
+		uow := &UnitOfWork{ctx: sc, bus: b}
+		if err := fn(uow); err != nil {
+			return nil, err
+		}
+
+		if len(uow.events) == 0 {
+			return nil, nil
+		}
+
+		now := time.Now()
+		entries := make([]interface{}, len(uow.events))
+		for i, event := range uow.events {
+			payload, err := bson.Marshal(event)
+			if err != nil {
+				return nil, err
+			}
+			entries[i] = models.OutboxEntry{
+				Event:       event.EventName(),
+				Payload:     payload,
+				Status:      models.OutboxPending,
+				AvailableAt: now,
+				CreatedAt:   now,
+			}
+		}
+
+		if _, err := config.DB.Collection("outbox").InsertMany(sc, entries); err != nil {
+			return nil, err
+		}
+		published = len(entries)
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if published > 0 {
+		wakeRelay()
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package invoices
+
+import (
+	"backend/config"
+	"backend/contracts"
+	"backend/events"
+	"backend/models"
+	"backend/pdf"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultFee is the flat placement fee in birr charged while no fee rule
+// applies, from PLACEMENT_FEE_ETB
+func DefaultFee() float64 {
+	if fee, err := strconv.ParseFloat(os.Getenv("PLACEMENT_FEE_ETB"), 64); err == nil && fee > 0 {
+		return fee
+	}
+	return 1000
+}
+
+// Fee returns what rule charges for a placement paying salary a month
+func Fee(rule models.FeeRule, salary float64) float64 {
+	if rule.Type != models.PercentageFee {
+		return rule.Amount
+	}
+	fee := math.Round(salary*rule.Percent) / 100
+	return math.Max(fee, rule.MinimumAmount)
+}
+
+// Validate reports why rule cannot price a placement
+func Validate(rule models.FeeRule) error {
+	switch {
+	case rule.Type == models.FlatFee && rule.Amount <= 0:
+		return fmt.Errorf("a flat fee needs an amount")
+	case rule.Type == models.PercentageFee && rule.Percent <= 0:
+		return fmt.Errorf("a percentage fee needs a percent")
+	}
+	return nil
+}
+
+// Issue creates the placement fee invoice of an approved hiring, or returns
+// the one issued before
+func Issue(ctx context.Context, hiring models.Hiring) (models.Invoice, error) {
+	invoice, err := find(ctx, hiring.ID)
+	if err != mongo.ErrNoDocuments {
+		return invoice, err
+	}
+
+	var housekeeper models.Housekeeper
+	if err := config.DB.Collection("housekeepers").FindOne(ctx, bson.M{"_id": hiring.HousekeeperID}).Decode(&housekeeper); err != nil {
+		return models.Invoice{}, err
+	}
+
+	rule, err := currentRule(ctx, housekeeper.Category)
+	if err != nil {
+		return models.Invoice{}, err
+	}
+
+	now := time.Now()
+	line := models.InvoiceLine{
+		Description: "Placement fee: " + housekeeper.Name,
+		Amount:      Fee(rule, hiring.SalaryOffer),
+	}
+	if rule.Type == models.PercentageFee {
+		line.Description += fmt.Sprintf(", %g%% of %s salary", rule.Percent, contracts.Money(hiring.SalaryOffer))
+	}
+	invoice = models.Invoice{
+		HiringID:      hiring.ID,
+		EmployerID:    hiring.EmployerID,
+		HousekeeperID: hiring.HousekeeperID,
+		FeeRuleID:     rule.ID,
+		Lines:         []models.InvoiceLine{line},
+		Total:         line.Amount,
+		Currency:      "ETB",
+		Status:        models.InvoiceUnpaid,
+		IssuedAt:      now,
+		UpdatedAt:     now,
+	}
+	if invoice.Total <= 0 {
+		// Nothing to collect
+		invoice.Status = models.InvoicePaid
+		invoice.PaidAt = now
+	}
+
+	err = events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		// Numbering inside the transaction leaves no gaps when the insert
+		// fails
+		number, err := nextNumber(uow.Context(), now.Year())
+		if err != nil {
+			return err
+		}
+		invoice.Number = number
+
+		result, err := config.DB.Collection("invoices").InsertOne(uow.Context(), invoice)
+		if err != nil {
+			return err
+		}
+		invoice.ID = result.InsertedID.(primitive.ObjectID)
+
+		return uow.Publish(events.InvoiceIssued{Invoice: invoice})
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		// Issued concurrently
+		return find(ctx, hiring.ID)
+	}
+	return invoice, err
+}
+
+// UpdateForPayment moves the invoice a payment collects along with the
+// payment. It runs inside the payment's transaction.
+func UpdateForPayment(ctx context.Context, payment models.Payment) error {
+	if payment.InvoiceID.IsZero() {
+		return nil
+	}
+
+	now := time.Now()
+	set := bson.M{"updated_at": now}
+	switch payment.Status {
+	case models.PaymentHeld:
+		set["status"] = models.InvoicePaid
+		set["paid_at"] = payment.PaidAt
+	case models.PaymentRefunded:
+		set["status"] = models.InvoiceRefunded
+		set["refunded_at"] = payment.RefundedAt
+	case models.PaymentCancelled:
+		set["status"] = models.InvoiceVoid
+	case models.PaymentFailed:
+		set["status"] = models.InvoiceUnpaid
+	default:
+		return nil
+	}
+
+	_, err := config.DB.Collection("invoices").UpdateOne(ctx, bson.M{"_id": payment.InvoiceID}, bson.M{"$set": set})
+	return err
+}
+
+// Document renders invoice as a PDF
+func Document(invoice models.Invoice, employer models.Employer) []byte {
+	doc := pdf.New("Invoice " + invoice.Number)
+	doc.Title("Invoice " + invoice.Number)
+	doc.Note(fmt.Sprintf("Issued %s - %s", invoice.IssuedAt.Format("2 January 2006"), statusLabels[invoice.Status]))
+	doc.Rule()
+
+	doc.Heading("Billed to")
+	doc.Paragraph(strings.Join([]string{employer.Name, employer.Address, employer.PhoneNumber, employer.Email}, "\n"))
+
+	doc.Heading("Details")
+	offsets := []float64{0, 400}
+	doc.Columns([]string{"Description", "Amount"}, offsets, true)
+	doc.Rule()
+	for _, line := range invoice.Lines {
+		doc.Columns([]string{line.Description, contracts.Money(line.Amount)}, offsets, false)
+	}
+	doc.Rule()
+	doc.Columns([]string{"Total", contracts.Money(invoice.Total)}, offsets, true)
+
+	doc.Space(12)
+	switch invoice.Status {
+	case models.InvoicePaid:
+		doc.Paragraph("Paid on " + invoice.PaidAt.Format("2 January 2006") + ". Thank you.")
+	case models.InvoiceRefunded:
+		doc.Paragraph("Refunded on " + invoice.RefundedAt.Format("2 January 2006") + ".")
+	case models.InvoiceVoid:
+		doc.Paragraph("This invoice is void; nothing is owed.")
+	default:
+		doc.Paragraph("Please pay through the link in the AGAZH app. The fee is held until the trial period of the placement ends and refunded if the placement is cancelled.")
+	}
+	doc.Note("Hiring " + invoice.HiringID.Hex())
+
+	return doc.Bytes()
+}
+
+var statusLabels = map[models.InvoiceStatus]string{
+	models.InvoiceUnpaid:   "Unpaid",
+	models.InvoicePaid:     "Paid",
+	models.InvoiceRefunded: "Refunded",
+	models.InvoiceVoid:     "Void",
+}
+
+func find(ctx context.Context, hiringID primitive.ObjectID) (models.Invoice, error) {
+	var invoice models.Invoice
+	err := config.DB.Collection("invoices").FindOne(ctx, bson.M{"hiring_id": hiringID}).Decode(&invoice)
+	return invoice, err
+}
+
+func currentRule(ctx context.Context, category models.Category) (models.FeeRule, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	for _, filter := range []bson.M{
+		{"category": category},
+		{"category": bson.M{"$exists": false}},
+	} {
+		var rule models.FeeRule
+		err := config.DB.Collection("fee_rules").FindOne(ctx, filter, opts).Decode(&rule)
+		if err == nil {
+			return rule, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return models.FeeRule{}, err
+		}
+	}
+	return models.FeeRule{Name: "Default", Type: models.FlatFee, Amount: DefaultFee()}, nil
+}
+
+// nextNumber takes the next invoice number of year from its counter
+func nextNumber(ctx context.Context, year int) (string, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := config.DB.Collection("counters").FindOneAndUpdate(ctx,
+		bson.M{"_id": fmt.Sprintf("invoice:%d", year)},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("AGZ-%d-%06d", year, counter.Seq), nil
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// pendingExpiry is how long a hiring request may wait for an answer,
+// configured with HIRING_PENDING_EXPIRY_DAYS
+func pendingExpiry() time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("HIRING_PENDING_EXPIRY_DAYS")); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return 7 * 24 * time.Hour
+}
+
+// ExpirePendingHirings moves hiring requests left unanswered for too long to
+// EXPIRED. The status change is published like any other, so the employer is
+// notified and open interviews are cancelled.
+func ExpirePendingHirings(ctx context.Context) error {
+	cutoff := time.Now().Add(-pendingExpiry())
+	cursor, err := config.DB.Collection("hirings").Find(ctx,
+		bson.M{"status": models.Pending, "created_at": bson.M{"$lte": cutoff}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return err
+	}
+
+	var stale []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &stale); err != nil {
+		return err
+	}
+
+	for _, doc := range stale {
+		if err := expireHiring(ctx, doc.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expireHiring(ctx context.Context, hiringID primitive.ObjectID) error {
+	return events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		var hiring models.Hiring
+		// Matching on the status skips hirings answered since the query
+		err := config.DB.Collection("hirings").FindOneAndUpdate(uow.Context(),
+			bson.M{"_id": hiringID, "status": models.Pending},
+			bson.M{"$set": bson.M{"status": models.Expired, "update_at": time.Now()}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&hiring)
+		if err != nil {
+			return ignoreNoDocuments(err)
+		}
+
+		return uow.Publish(events.HiringStatusChanged{Hiring: hiring, PreviousStatus: models.Pending})
+	})
+}
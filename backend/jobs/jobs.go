@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"backend/notifications"
+	"backend/payments"
+	"backend/payroll"
+	"backend/reminders"
+	"backend/scheduler"
+	"backend/webhooks"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Register adds every scheduled job of the backend to s
+func Register(s *scheduler.Scheduler) error {
+	if err := s.Add("expire-pending-hirings", "0 * * * *", 10*time.Minute, ExpirePendingHirings); err != nil {
+		return err
+	}
+	if err := s.Add("complete-trials", "30 * * * *", 10*time.Minute, CompleteTrials); err != nil {
+		return err
+	}
+	if err := s.Add("open-payroll-periods", "0 3 * * *", 30*time.Minute, payroll.OpenCurrentPeriods); err != nil {
+		return err
+	}
+	if err := s.Add("hiring-start-reminders", "*/15 * * * *", 5*time.Minute, reminders.SendHiringStartReminders); err != nil {
+		return err
+	}
+	if err := s.Add("interview-reminders", "*/5 * * * *", 5*time.Minute, reminders.SendInterviewReminders); err != nil {
+		return err
+	}
+	if err := s.Add("webhook-deliveries", "* * * * *", 5*time.Minute, webhooks.RetryDeliveries); err != nil {
+		return err
+	}
+	if err := s.Add("payment-refunds", "*/10 * * * *", 5*time.Minute, payments.RetryRefunds); err != nil {
+		return err
+	}
+	return s.Add("push-receipts", "*/15 * * * *", 5*time.Minute, notifications.CheckPushReceipts)
+}
+
+func ignoreNoDocuments(err error) error {
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	return err
+}
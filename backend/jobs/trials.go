@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CompleteTrials marks the trial of approved placements as passed once its
+// end date is reached, after which no replacement can be requested
+func CompleteTrials(ctx context.Context) error {
+	cursor, err := config.DB.Collection("hirings").Find(ctx,
+		bson.M{
+			"status":        models.Approved,
+			"trial.status":  models.TrialActive,
+			"trial.ends_at": bson.M{"$lte": time.Now()},
+		},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return err
+	}
+
+	var due []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &due); err != nil {
+		return err
+	}
+
+	for _, doc := range due {
+		if err := completeTrial(ctx, doc.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func completeTrial(ctx context.Context, hiringID primitive.ObjectID) error {
+	return events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		var hiring models.Hiring
+		// Matching on the statuses skips placements replaced or ended since
+		// the query
+		err := config.DB.Collection("hirings").FindOneAndUpdate(uow.Context(),
+			bson.M{"_id": hiringID, "status": models.Approved, "trial.status": models.TrialActive},
+			bson.M{"$set": bson.M{"trial.status": models.TrialPassed, "update_at": time.Now()}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&hiring)
+		if err != nil {
+			return ignoreNoDocuments(err)
+		}
+
+		return uow.Publish(events.TrialPassed{Hiring: hiring})
+	})
+}
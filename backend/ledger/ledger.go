@@ -0,0 +1,126 @@
+// Package ledger keeps the double-entry accounts of the money AGAZH handles.
+// Every movement is a transaction whose debits equal its credits, so money is
+// never created or lost between accounts. Amounts are in santim, a hundredth
+// of a birr, to keep sums exact.
+//
+// Provider accounts are assets: money paid in is a debit. Escrow and revenue
+// accounts are what AGAZH owes or has earned, so they grow with credits and
+// carry negative balances.
+package ledger
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PlacementFeeRevenue collects placement fees once they are earned
+const PlacementFeeRevenue = "revenue:placement_fees"
+
+var (
+	ErrUnbalanced = errors.New("ledger transaction does not balance")
+	// ErrPosted is returned when a transaction with the same key was posted
+	// before
+	ErrPosted = errors.New("ledger transaction already posted")
+)
+
+// ProviderAccount holds the money that sits with a payment provider
+func ProviderAccount(provider string) string {
+	return "provider:" + provider
+}
+
+// EscrowAccount holds the money paid for a hiring until it is earned or
+// refunded
+func EscrowAccount(hiringID primitive.ObjectID) string {
+	return "escrow:" + hiringID.Hex()
+}
+
+// Santim converts an amount in birr to santim
+func Santim(birr float64) int64 {
+	return int64(math.Round(birr * 100))
+}
+
+// Entry returns the postings that debit one account and credit another with
+// amount santim
+func Entry(debit, credit string, amount int64) []models.Posting {
+	return []models.Posting{
+		{Account: debit, Debit: amount},
+		{Account: credit, Credit: amount},
+	}
+}
+
+// Post records tx and updates the balances of its accounts. It must run
+// inside a transaction, so the postings and balances are written together.
+func Post(ctx context.Context, tx models.LedgerTransaction) error {
+	if tx.Key == "" {
+		return fmt.Errorf("ledger transaction needs a key")
+	}
+
+	var debits, credits int64
+	changes := map[string]int64{}
+	tx.Accounts = nil
+	for _, posting := range tx.Postings {
+		if posting.Debit < 0 || posting.Credit < 0 {
+			return fmt.Errorf("ledger posting to %s has a negative amount", posting.Account)
+		}
+		debits += posting.Debit
+		credits += posting.Credit
+		if _, seen := changes[posting.Account]; !seen {
+			tx.Accounts = append(tx.Accounts, posting.Account)
+		}
+		changes[posting.Account] += posting.Debit - posting.Credit
+	}
+	if debits != credits || debits == 0 {
+		return ErrUnbalanced
+	}
+
+	// A failed write aborts a Mongo transaction, so look the key up instead
+	// of relying on the unique index to reject it
+	count, err := config.DB.Collection("ledger_transactions").CountDocuments(ctx, bson.M{"key": tx.Key})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPosted
+	}
+
+	tx.ID = primitive.NilObjectID
+	tx.CreatedAt = time.Now()
+	if _, err := config.DB.Collection("ledger_transactions").InsertOne(ctx, tx); err != nil {
+		return err
+	}
+
+	for _, account := range tx.Accounts {
+		_, err := config.DB.Collection("ledger_accounts").UpdateOne(ctx,
+			bson.M{"_id": account},
+			bson.M{
+				"$inc": bson.M{"balance": changes[account]},
+				"$set": bson.M{"updated_at": tx.CreatedAt},
+			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Balance returns the balance of account, debits minus credits
+func Balance(ctx context.Context, account string) (int64, error) {
+	var ledgerAccount models.LedgerAccount
+	err := config.DB.Collection("ledger_accounts").FindOne(ctx, bson.M{"_id": account}).Decode(&ledgerAccount)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	return ledgerAccount.Balance, err
+}
@@ -2,9 +2,18 @@ package main
 
 import (
 	"backend/config"
+	"backend/events"
+	"backend/jobs"
+	"backend/moderation"
+	"backend/notifications"
+	"backend/payments"
 	"backend/routes"
+	"backend/scheduler"
+	"backend/subscribers"
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -21,6 +30,25 @@ func main() {
 	if err := config.ConnectDB(); err != nil {
 		log.Fatal(err)
 	}
+	if err := config.EnsureIndexes(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := moderation.Init(); err != nil {
+		log.Fatal(err)
+	}
+	notifications.Init()
+	if err := payments.Init(); err != nil {
+		log.Fatal(err)
+	}
+	subscribers.Register(events.Default)
+	go events.StartRelay(context.Background(), 10*time.Second)
+
+	jobScheduler := scheduler.New()
+	if err := jobs.Register(jobScheduler); err != nil {
+		log.Fatal(err)
+	}
+	go jobScheduler.Start(context.Background(), 30*time.Second)
 
 	r := gin.Default()
 
@@ -40,6 +68,11 @@ func main() {
 		routes.SetupEmployerRoutes(v1)
 		routes.SetupHiringRoutes(v1)
 		routes.SetupRatingRoutes(v1)
+		routes.SetupDeviceRoutes(v1)
+		routes.SetupNotificationRoutes(v1)
+		routes.SetupWebhookRoutes(v1)
+		routes.SetupPaymentRoutes(v1)
+		routes.SetupAdminRoutes(v1)
 	}
 
 	r.Static("/docs", "./docs")
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditLog is an append-only record of a domain event
+type AuditLog struct {
+	ID         primitive.ObjectID            `json:"id,omitempty" bson:"_id,omitempty"`
+	Event      string                        `json:"event,omitempty" bson:"event,omitempty"`
+	Refs       map[string]primitive.ObjectID `json:"refs,omitempty" bson:"refs,omitempty"`
+	Details    map[string]interface{}        `json:"details,omitempty" bson:"details,omitempty"`
+	OccurredAt time.Time                     `json:"occurred_at,omitempty" bson:"occurred_at,omitempty"`
+}
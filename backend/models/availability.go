@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Weekday string
+
+const (
+	Monday    Weekday = "MONDAY"
+	Tuesday   Weekday = "TUESDAY"
+	Wednesday Weekday = "WEDNESDAY"
+	Thursday  Weekday = "THURSDAY"
+	Friday    Weekday = "FRIDAY"
+	Saturday  Weekday = "SATURDAY"
+	Sunday    Weekday = "SUNDAY"
+)
+
+// Weekdays is indexed by time.Weekday
+var Weekdays = []Weekday{Sunday, Monday, Tuesday, Wednesday, Thursday, Friday, Saturday}
+
+// WeekdayOf returns the weekday of t
+func WeekdayOf(t time.Time) Weekday {
+	return Weekdays[t.Weekday()]
+}
+
+// Availability is when a housekeeper is free to work: a weekly
+// schedule, overridden on some dates by exceptions. A housekeeper without a
+// weekly schedule is treated as available every day.
+type Availability struct {
+	Weekly     []WeeklySlot            `json:"weekly" bson:"weekly"`
+	Exceptions []AvailabilityException `json:"exceptions" bson:"exceptions"`
+	UpdatedAt  time.Time               `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// WeeklySlot is a recurring window of availability, with times written as
+// 24-hour HH:MM
+type WeeklySlot struct {
+	Day   Weekday `json:"day" bson:"day" binding:"required,oneof=MONDAY TUESDAY WEDNESDAY THURSDAY FRIDAY SATURDAY SUNDAY"`
+	Start string  `json:"start" bson:"start" binding:"required,datetime=15:04"`
+	End   string  `json:"end" bson:"end" binding:"required,datetime=15:04"`
+}
+
+// Minutes returns the start and end of the slot as minutes after midnight
+func (s WeeklySlot) Minutes() (int, int, error) {
+	start, err := time.Parse("15:04", s.Start)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := time.Parse("15:04", s.End)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), nil
+}
+
+// AvailabilityException overrides the weekly schedule from one date to
+// another, both included. Available exceptions add days off the schedule,
+// unavailable ones block days on it, such as holidays.
+type AvailabilityException struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	From      time.Time          `json:"from" bson:"from"`
+	To        time.Time          `json:"to" bson:"to"`
+	Available bool               `json:"available" bson:"available"`
+	Note      string             `json:"note,omitempty" bson:"note,omitempty"`
+}
+
+// WeeklyScheduleUpdate is the request body for replacing a weekly schedule
+type WeeklyScheduleUpdate struct {
+	Weekly []WeeklySlot `json:"weekly" binding:"max=21,dive"`
+}
+
+// AvailabilityExceptionRequest is the request body for adding an exception.
+// Dates are written as YYYY-MM-DD.
+type AvailabilityExceptionRequest struct {
+	From      string `json:"from" binding:"required,datetime=2006-01-02"`
+	To        string `json:"to" binding:"required,datetime=2006-01-02"`
+	Available bool   `json:"available"`
+	Note      string `json:"note" binding:"max=300"`
+}
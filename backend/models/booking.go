@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BookingMode string
+
+const (
+	// PlacementBooking is a long-term placement paid a monthly salary
+	PlacementBooking BookingMode = "PLACEMENT"
+	// HourlyBooking is one-off or recurring work paid by the hour
+	HourlyBooking BookingMode = "HOURLY"
+)
+
+type Recurrence string
+
+const (
+	NoRecurrence Recurrence = "NONE"
+	Weekly       Recurrence = "WEEKLY"
+	Biweekly     Recurrence = "BIWEEKLY"
+)
+
+// Booking describes the visits of an hourly hiring. Recurring bookings repeat
+// FirstStart every week or every other week, Occurrences times in total.
+type Booking struct {
+	FirstStart      time.Time  `json:"first_start" bson:"first_start" binding:"required"`
+	DurationMinutes int        `json:"duration_minutes" bson:"duration_minutes" binding:"required,min=60,max=720"`
+	HourlyRate      float64    `json:"hourly_rate" bson:"hourly_rate" binding:"required,gt=0"`
+	Recurrence      Recurrence `json:"recurrence,omitempty" bson:"recurrence,omitempty" binding:"omitempty,oneof=NONE WEEKLY BIWEEKLY"`
+	Occurrences     int        `json:"occurrences,omitempty" bson:"occurrences,omitempty" binding:"omitempty,min=1,max=52"`
+}
+
+type OccurrenceStatus string
+
+const (
+	OccurrenceScheduled OccurrenceStatus = "SCHEDULED"
+	OccurrenceCompleted OccurrenceStatus = "COMPLETED"
+	// OccurrenceSkipped is a visit the employer does not need; the rest of
+	// the booking goes on
+	OccurrenceSkipped   OccurrenceStatus = "SKIPPED"
+	OccurrenceCancelled OccurrenceStatus = "CANCELLED"
+)
+
+// Occurrence is a single visit of an hourly booking
+type Occurrence struct {
+	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID      primitive.ObjectID `json:"hiring_id,omitempty" bson:"hiring_id,omitempty"`
+	EmployerID    primitive.ObjectID `json:"employer_id,omitempty" bson:"employer_id,omitempty"`
+	HousekeeperID primitive.ObjectID `json:"housekeeper_id,omitempty" bson:"housekeeper_id,omitempty"`
+	Sequence      int                `json:"sequence" bson:"sequence"`
+	StartsAt      time.Time          `json:"starts_at" bson:"starts_at"`
+	EndsAt        time.Time          `json:"ends_at" bson:"ends_at"`
+	Amount        float64            `json:"amount" bson:"amount"`
+	Status        OccurrenceStatus   `json:"status" bson:"status"`
+	ChangedBy     string             `json:"changed_by,omitempty" bson:"changed_by,omitempty"`
+	Reason        string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	UpdatedAt     time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// OccurrenceChange is the request body for skipping or cancelling a visit
+type OccurrenceChange struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
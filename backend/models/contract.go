@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContractTemplate is the text of an employment contract, written as a Go
+// text/template over the hiring, employer and housekeeper. Lines starting
+// with "# " are printed as headings. The newest template for the employment
+// type of a hiring is used, falling back to the newest template without one.
+type ContractTemplate struct {
+	ID             primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name           string             `json:"name" bson:"name" binding:"required,max=200"`
+	EmploymentType EmploymentType     `json:"employment_type,omitempty" bson:"employment_type,omitempty" binding:"omitempty,oneof=LIVE_OUT LIVE_IN"`
+	Body           string             `json:"body" bson:"body" binding:"required,max=50000"`
+	CreatedBy      primitive.ObjectID `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+}
+
+type ContractStatus string
+
+const (
+	ContractAwaitingSignatures ContractStatus = "AWAITING_SIGNATURES"
+	ContractSigned             ContractStatus = "SIGNED"
+	// ContractSuperseded marks a version replaced by a newer one before both
+	// parties signed it
+	ContractSuperseded ContractStatus = "SUPERSEDED"
+)
+
+// Contract is one version of the employment contract of a hiring. The
+// rendered text and its PDF are stored, so later template or profile changes
+// do not alter what was signed.
+type Contract struct {
+	ID             primitive.ObjectID  `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID       primitive.ObjectID  `json:"hiring_id" bson:"hiring_id"`
+	EmployerID     primitive.ObjectID  `json:"employer_id" bson:"employer_id"`
+	HousekeeperID  primitive.ObjectID  `json:"housekeeper_id" bson:"housekeeper_id"`
+	Version        int                 `json:"version" bson:"version"`
+	TemplateID     primitive.ObjectID  `json:"template_id,omitempty" bson:"template_id,omitempty"`
+	Body           string              `json:"body" bson:"body"`
+	Hash           string              `json:"hash" bson:"hash"`
+	Status         ContractStatus      `json:"status" bson:"status"`
+	Signatures     []ContractSignature `json:"signatures" bson:"signatures"`
+	Document       []byte              `json:"-" bson:"document"`
+	SignedDocument []byte              `json:"-" bson:"signed_document,omitempty"`
+	CreatedAt      time.Time           `json:"created_at" bson:"created_at"`
+	SignedAt       time.Time           `json:"signed_at,omitempty" bson:"signed_at,omitempty"`
+}
+
+// ContractSignature records a party accepting a contract version
+type ContractSignature struct {
+	Party     string             `json:"party" bson:"party"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Name      string             `json:"name" bson:"name"`
+	IP        string             `json:"ip" bson:"ip"`
+	UserAgent string             `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	SignedAt  time.Time          `json:"signed_at" bson:"signed_at"`
+}
+
+// ContractAcceptance is the request body for signing a contract. Hash must
+// be the hash of the version the user read.
+type ContractAcceptance struct {
+	Agree bool   `json:"agree" binding:"required"`
+	Hash  string `json:"hash" binding:"required"`
+}
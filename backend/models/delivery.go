@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeliveryType is how a placed housekeeper gets to the employer
+type DeliveryType string
+
+const (
+	// HomeDelivery means AGAZH staff bring the housekeeper to the employer
+	HomeDelivery DeliveryType = "DELIVERY"
+	// OfficePickup means the employer picks the housekeeper up at the office
+	OfficePickup DeliveryType = "PICKUP"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryScheduled DeliveryStatus = "SCHEDULED"
+	DeliveryEnRoute   DeliveryStatus = "EN_ROUTE"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	// DeliveryCancelled marks a delivery whose hiring ended before the
+	// housekeeper was handed over
+	DeliveryCancelled DeliveryStatus = "CANCELLED"
+)
+
+// Delivery is the logistics record for handing a placed housekeeper over to
+// the employer, planned by AGAZH staff once the hiring is approved
+type Delivery struct {
+	ID                 primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID           primitive.ObjectID `json:"hiring_id" bson:"hiring_id"`
+	EmployerID         primitive.ObjectID `json:"employer_id" bson:"employer_id"`
+	HousekeeperID      primitive.ObjectID `json:"housekeeper_id" bson:"housekeeper_id"`
+	Type               DeliveryType       `json:"type" bson:"type"`
+	Status             DeliveryStatus     `json:"status" bson:"status"`
+	ScheduledAt        time.Time          `json:"scheduled_at" bson:"scheduled_at"`
+	PickupAddress      string             `json:"pickup_address" bson:"pickup_address"`
+	DestinationAddress string             `json:"destination_address" bson:"destination_address"`
+	StaffID            primitive.ObjectID `json:"staff_id" bson:"staff_id"`
+	StaffName          string             `json:"staff_name,omitempty" bson:"staff_name,omitempty"`
+	Notes              string             `json:"notes,omitempty" bson:"notes,omitempty"`
+	DepartedAt         time.Time          `json:"departed_at,omitempty" bson:"departed_at,omitempty"`
+	DeliveredAt        time.Time          `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+	CreatedAt          time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// DeliverySchedule is the request body for rescheduling a delivery. Empty
+// addresses default to the AGAZH office for pickup and the employer's address
+// as destination, or the office as well for an office pickup.
+type DeliverySchedule struct {
+	ScheduledAt        time.Time          `json:"scheduled_at" binding:"required"`
+	PickupAddress      string             `json:"pickup_address,omitempty" binding:"max=300"`
+	DestinationAddress string             `json:"destination_address,omitempty" binding:"max=300"`
+	StaffID            primitive.ObjectID `json:"staff_id" binding:"required"`
+	Notes              string             `json:"notes,omitempty" binding:"max=1000"`
+}
+
+// DeliveryPlan is the request body for planning the delivery of a hiring
+type DeliveryPlan struct {
+	HiringID primitive.ObjectID `json:"hiring_id" binding:"required"`
+	DeliverySchedule
+}
+
+// DeliveryStatusUpdate is the request body for moving a delivery forward
+type DeliveryStatusUpdate struct {
+	Status DeliveryStatus `json:"status" binding:"required,oneof=EN_ROUTE DELIVERED"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PushReceiptStatus string
+
+const (
+	PushReceiptPending PushReceiptStatus = "PENDING"
+	PushReceiptOK      PushReceiptStatus = "OK"
+	PushReceiptError   PushReceiptStatus = "ERROR"
+)
+
+// DeviceToken is a push token registered by a user for one of their devices
+type DeviceToken struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	UserType  string             `json:"user_type,omitempty" bson:"user_type,omitempty"`
+	DeviceID  string             `json:"device_id,omitempty" bson:"device_id,omitempty" binding:"required"`
+	Token     string             `json:"token,omitempty" bson:"token,omitempty" binding:"required"`
+	Platform  string             `json:"platform,omitempty" bson:"platform,omitempty"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// PushReceipt tracks the delivery of a single push message to a device
+type PushReceipt struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TicketID  string             `json:"ticket_id,omitempty" bson:"ticket_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Token     string             `json:"token,omitempty" bson:"token,omitempty"`
+	Event     string             `json:"event,omitempty" bson:"event,omitempty"`
+	Status    PushReceiptStatus  `json:"status,omitempty" bson:"status,omitempty"`
+	Error     string             `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	CheckedAt time.Time          `json:"checked_at,omitempty" bson:"checked_at,omitempty"`
+}
@@ -16,6 +16,11 @@ type Employer struct {
 	ReligionPreference     string             `json:"religion_preference,omitempty" bson:"religion_preference,omitempty"`
 	PlaceOfBirthPreference string             `json:"place_of_birth_preference,omitempty" bson:"place_of_birth_preference,omitempty"`
 	FamilySize             int                `json:"family_size,omitempty" bson:"family_size,omitempty"`
+	SMSOptIn               bool               `json:"sms_opt_in,omitempty" bson:"sms_opt_in,omitempty"`
+	Rating                 float64            `json:"rating,omitempty" bson:"rating,omitempty"`
+	RatingCount            int                `json:"rating_count,omitempty" bson:"rating_count,omitempty"`
+	RatingSum              float64            `json:"-" bson:"rating_sum,omitempty"`
+	Reliability            Reliability        `json:"reliability" bson:"reliability"`
 	CreatedAt              time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
 	UpdatedAt              time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
 }
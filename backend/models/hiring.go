@@ -9,21 +9,89 @@ import (
 type HiringStatus string
 
 const (
-	Pending   HiringStatus = "PENDING"
-	Approved  HiringStatus = "APPROVED"
-	Rejected  HiringStatus = "REJECTED"
-	Completed HiringStatus = "COMPLETED"
+	Pending    HiringStatus = "PENDING"
+	Approved   HiringStatus = "APPROVED"
+	Rejected   HiringStatus = "REJECTED"
+	Completed  HiringStatus = "COMPLETED"
+	Cancelled  HiringStatus = "CANCELLED"
+	Terminated HiringStatus = "TERMINATED"
+	Expired    HiringStatus = "EXPIRED"
 )
 
+type CancellationReason string
+
+// Reasons an employer can give for cancelling
+const (
+	FoundAnotherHousekeeper CancellationReason = "FOUND_ANOTHER_HOUSEKEEPER"
+	NoLongerNeeded          CancellationReason = "NO_LONGER_NEEDED"
+	BudgetChanged           CancellationReason = "BUDGET_CHANGED"
+	HousekeeperUnresponsive CancellationReason = "HOUSEKEEPER_UNRESPONSIVE"
+)
+
+// Reasons a housekeeper can give for cancelling
+const (
+	AcceptedAnotherJob    CancellationReason = "ACCEPTED_ANOTHER_JOB"
+	PersonalReasons       CancellationReason = "PERSONAL_REASONS"
+	TermsChanged          CancellationReason = "TERMS_CHANGED"
+	EmployerUnresponsive  CancellationReason = "EMPLOYER_UNRESPONSIVE"
+	UnsafeWorkEnvironment CancellationReason = "UNSAFE_WORK_ENVIRONMENT"
+)
+
+// OtherReason can be given by either party, together with a note
+const OtherReason CancellationReason = "OTHER"
+
+// CancellationReasons lists the reasons each party may give
+var CancellationReasons = map[string][]CancellationReason{
+	"employer":    {FoundAnotherHousekeeper, NoLongerNeeded, BudgetChanged, HousekeeperUnresponsive, OtherReason},
+	"housekeeper": {AcceptedAnotherJob, PersonalReasons, TermsChanged, EmployerUnresponsive, UnsafeWorkEnvironment, OtherReason},
+}
+
 type Hiring struct {
-	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	EmployerID    primitive.ObjectID `json:"employer_id,omitempty" bson:"employer_id,omitempty"`
-	HousekeeperID primitive.ObjectID `json:"housekeeper_id,omitempty" bson:"housekeeper_id,omitempty"`
-	Status        HiringStatus       `json:"status,omitempty" bson:"status,omitempty"`
-	Requirements  string             `json:"requirements,omitempty" bson:"requirements,omitempty"`
-	SalaryOffer   float64            `json:"salary_offer,omitempty" bson:"salary_offer,omitempty"`
-	StartDate     time.Time          `json:"start_date,omitempty" bson:"start_date,omitempty"`
-	DeliveryType  string             `json:"delivery_type,omitempty" bson:"delivery_type,omitempty"`
-	CreatedAt     time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt     time.Time          `json:"update_at,omitempty" bson:"update_at,omitempty"`
+	ID                   primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	EmployerID           primitive.ObjectID `json:"employer_id,omitempty" bson:"employer_id,omitempty"`
+	HousekeeperID        primitive.ObjectID `json:"housekeeper_id,omitempty" bson:"housekeeper_id,omitempty"`
+	Status               HiringStatus       `json:"status,omitempty" bson:"status,omitempty"`
+	Requirements         string             `json:"requirements,omitempty" bson:"requirements,omitempty"`
+	SalaryOffer          float64            `json:"salary_offer,omitempty" bson:"salary_offer,omitempty"`
+	StartDate            time.Time          `json:"start_date,omitempty" bson:"start_date,omitempty"`
+	EndDate              time.Time          `json:"end_date,omitempty" bson:"end_date,omitempty"`
+	EmploymentType       EmploymentType     `json:"employment_type,omitempty" bson:"employment_type,omitempty"`
+	DeliveryType         DeliveryType       `json:"delivery_type,omitempty" bson:"delivery_type,omitempty" binding:"omitempty,oneof=DELIVERY PICKUP"`
+	BookingMode          BookingMode        `json:"booking_mode,omitempty" bson:"booking_mode,omitempty"`
+	Booking              *Booking           `json:"booking,omitempty" bson:"booking,omitempty"`
+	Cancellation         *Cancellation      `json:"cancellation,omitempty" bson:"cancellation,omitempty"`
+	AcceptedOfferID      primitive.ObjectID `json:"accepted_offer_id,omitempty" bson:"accepted_offer_id,omitempty"`
+	Trial                *Trial             `json:"trial,omitempty" bson:"trial,omitempty"`
+	ReplacementFor       primitive.ObjectID `json:"replacement_for,omitempty" bson:"replacement_for,omitempty"`
+	PensionEnrolled      bool               `json:"pension_enrolled,omitempty" bson:"pension_enrolled,omitempty"`
+	EndedAt              time.Time          `json:"ended_at,omitempty" bson:"ended_at,omitempty"`
+	RemindedAt           time.Time          `json:"-" bson:"reminded_at,omitempty"`
+	ReminderChannels     []string           `json:"-" bson:"reminder_channels,omitempty"`
+	ReminderClaimedUntil time.Time          `json:"-" bson:"reminder_claimed_until,omitempty"`
+	CreatedAt            time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt            time.Time          `json:"update_at,omitempty" bson:"update_at,omitempty"`
+}
+
+// Cancellation records who cancelled a hiring, why and how close to its start
+type Cancellation struct {
+	CancelledBy string             `json:"cancelled_by" bson:"cancelled_by"`
+	Reason      CancellationReason `json:"reason" bson:"reason"`
+	Note        string             `json:"note,omitempty" bson:"note,omitempty"`
+	// Late is set when an approved hiring is cancelled within the notice
+	// period before its start date
+	Late        bool      `json:"late" bson:"late"`
+	CancelledAt time.Time `json:"cancelled_at" bson:"cancelled_at"`
+}
+
+// CancellationRequest is the request body for cancelling a hiring
+type CancellationRequest struct {
+	Reason CancellationReason `json:"reason" binding:"required"`
+	Note   string             `json:"note,omitempty" binding:"max=500"`
+}
+
+// Reliability counts how often a party backed out of approved hirings.
+// Withdrawn requests that were never approved are not counted.
+type Reliability struct {
+	CancellationCount     int `json:"cancellation_count" bson:"cancellation_count"`
+	LateCancellationCount int `json:"late_cancellation_count" bson:"late_cancellation_count"`
 }
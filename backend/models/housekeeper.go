@@ -35,8 +35,14 @@ type Housekeeper struct {
 	Religion       string             `json:"religion,omitempty" bson:"religion,omitempty"`
 	PlaceOfBirth   string             `json:"place_of_birth,omitempty" bson:"place_of_birth,omitempty"`
 	Rating         float64            `json:"rating,omitempty" bson:"rating,omitempty"`
+	RatingSummary  *RatingSummary     `json:"rating_summary,omitempty" bson:"rating_summary,omitempty"`
+	RankingScore   float64            `json:"ranking_score,omitempty" bson:"ranking_score,omitempty"`
 	Reviews        []Review           `json:"reviews,omitempty" bson:"reviews,omitempty"`
+	Reliability    Reliability        `json:"reliability" bson:"reliability"`
 	IsAvailable    bool               `json:"is_available,omitempty" bson:"is_available,omitempty"`
+	Availability   *Availability      `json:"availability,omitempty" bson:"availability,omitempty"`
+	SMSOptIn       bool               `json:"sms_opt_in,omitempty" bson:"sms_opt_in,omitempty"`
+	SearchKeywords []string           `json:"-" bson:"search_keywords,omitempty"`
 	CreatedAt      time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
 	UpdatedAt      time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
 }
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InterviewMode string
+
+const (
+	InPersonInterview InterviewMode = "IN_PERSON"
+	PhoneInterview    InterviewMode = "PHONE"
+)
+
+type InterviewStatus string
+
+const (
+	// InterviewProposed waits for the other party to pick one of the slots
+	InterviewProposed  InterviewStatus = "PROPOSED"
+	InterviewConfirmed InterviewStatus = "CONFIRMED"
+	InterviewCompleted InterviewStatus = "COMPLETED"
+	InterviewCancelled InterviewStatus = "CANCELLED"
+)
+
+// OpenInterviewStatuses are the statuses of interviews that still have to
+// take place
+var OpenInterviewStatuses = []InterviewStatus{InterviewProposed, InterviewConfirmed}
+
+type InterviewOutcome string
+
+const (
+	InterviewPassed InterviewOutcome = "PASSED"
+	InterviewFailed InterviewOutcome = "FAILED"
+)
+
+// Interview is a meeting or call between the parties of a pending hiring.
+// The employer proposes slots, the housekeeper confirms one or proposes
+// others, and after the interview the employer records the outcome.
+type Interview struct {
+	ID                   primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID             primitive.ObjectID `json:"hiring_id,omitempty" bson:"hiring_id,omitempty"`
+	EmployerID           primitive.ObjectID `json:"employer_id,omitempty" bson:"employer_id,omitempty"`
+	HousekeeperID        primitive.ObjectID `json:"housekeeper_id,omitempty" bson:"housekeeper_id,omitempty"`
+	Mode                 InterviewMode      `json:"mode,omitempty" bson:"mode,omitempty"`
+	Location             string             `json:"location,omitempty" bson:"location,omitempty"`
+	DurationMinutes      int                `json:"duration_minutes,omitempty" bson:"duration_minutes,omitempty"`
+	ProposedBy           string             `json:"proposed_by,omitempty" bson:"proposed_by,omitempty"`
+	Slots                []time.Time        `json:"slots,omitempty" bson:"slots,omitempty"`
+	Status               InterviewStatus    `json:"status,omitempty" bson:"status,omitempty"`
+	ScheduledAt          time.Time          `json:"scheduled_at,omitempty" bson:"scheduled_at,omitempty"`
+	EndsAt               time.Time          `json:"ends_at,omitempty" bson:"ends_at,omitempty"`
+	Outcome              InterviewOutcome   `json:"outcome,omitempty" bson:"outcome,omitempty"`
+	OutcomeNote          string             `json:"outcome_note,omitempty" bson:"outcome_note,omitempty"`
+	ReminderSentAt       time.Time          `json:"reminder_sent_at,omitempty" bson:"reminder_sent_at,omitempty"`
+	ReminderChannels     []string           `json:"-" bson:"reminder_channels,omitempty"`
+	ReminderClaimedUntil time.Time          `json:"-" bson:"reminder_claimed_until,omitempty"`
+	CreatedAt            time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt            time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// InterviewProposal is the request body for proposing or rescheduling an
+// interview. Mode, location and duration are kept when rescheduling without them.
+type InterviewProposal struct {
+	Mode            InterviewMode `json:"mode" binding:"omitempty,oneof=IN_PERSON PHONE"`
+	Location        string        `json:"location" binding:"max=300"`
+	DurationMinutes int           `json:"duration_minutes" binding:"omitempty,min=15,max=240"`
+	Slots           []time.Time   `json:"slots" binding:"required,min=1,max=5"`
+}
+
+// InterviewSlotChoice is the request body for confirming an interview
+type InterviewSlotChoice struct {
+	Slot time.Time `json:"slot" binding:"required"`
+}
+
+// InterviewResult is the request body for recording an interview's outcome
+type InterviewResult struct {
+	Outcome InterviewOutcome `json:"outcome" binding:"required,oneof=PASSED FAILED"`
+	Note    string           `json:"note" binding:"max=1000"`
+}
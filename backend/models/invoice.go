@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FeeRuleType string
+
+const (
+	// FlatFee charges Amount birr per placement
+	FlatFee FeeRuleType = "FLAT"
+	// PercentageFee charges Percent of the monthly salary offered
+	PercentageFee FeeRuleType = "PERCENTAGE"
+)
+
+// FeeRule sets the placement fee. The newest rule for the category of the
+// placed housekeeper is used, falling back to the newest rule without a
+// category. Rules are never edited, so invoices keep pointing at the rule
+// they were priced with.
+type FeeRule struct {
+	ID       primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name     string             `json:"name" bson:"name" binding:"required,max=200"`
+	Category Category           `json:"category,omitempty" bson:"category,omitempty" binding:"omitempty,oneof=NORMAL CHILD_CARE CLEANING"`
+	Type     FeeRuleType        `json:"type" bson:"type" binding:"required,oneof=FLAT PERCENTAGE"`
+	Amount   float64            `json:"amount,omitempty" bson:"amount,omitempty" binding:"gte=0"`
+	Percent  float64            `json:"percent,omitempty" bson:"percent,omitempty" binding:"gte=0,lte=100"`
+	// MinimumAmount is the least a percentage rule charges, for placements
+	// with a low or missing salary offer
+	MinimumAmount float64            `json:"minimum_amount,omitempty" bson:"minimum_amount,omitempty" binding:"gte=0"`
+	CreatedBy     primitive.ObjectID `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+}
+
+type InvoiceStatus string
+
+const (
+	InvoiceUnpaid   InvoiceStatus = "UNPAID"
+	InvoicePaid     InvoiceStatus = "PAID"
+	InvoiceRefunded InvoiceStatus = "REFUNDED"
+	// InvoiceVoid is an invoice that will not be paid because the hiring
+	// ended first
+	InvoiceVoid InvoiceStatus = "VOID"
+)
+
+// Invoice bills an employer for a placement. Its number is sequential per
+// year, such as AGZ-2026-000042. Amounts are in Ethiopian birr.
+type Invoice struct {
+	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Number        string             `json:"number" bson:"number"`
+	HiringID      primitive.ObjectID `json:"hiring_id" bson:"hiring_id"`
+	EmployerID    primitive.ObjectID `json:"employer_id" bson:"employer_id"`
+	HousekeeperID primitive.ObjectID `json:"housekeeper_id" bson:"housekeeper_id"`
+	FeeRuleID     primitive.ObjectID `json:"fee_rule_id,omitempty" bson:"fee_rule_id,omitempty"`
+	Lines         []InvoiceLine      `json:"lines" bson:"lines"`
+	Total         float64            `json:"total" bson:"total"`
+	Currency      string             `json:"currency" bson:"currency"`
+	Status        InvoiceStatus      `json:"status" bson:"status"`
+	IssuedAt      time.Time          `json:"issued_at" bson:"issued_at"`
+	PaidAt        time.Time          `json:"paid_at,omitempty" bson:"paid_at,omitempty"`
+	RefundedAt    time.Time          `json:"refunded_at,omitempty" bson:"refunded_at,omitempty"`
+	UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type InvoiceLine struct {
+	Description string  `json:"description" bson:"description"`
+	Amount      float64 `json:"amount" bson:"amount"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// JobRun is the state of a scheduled job shared by every instance. An
+// instance runs the job only after claiming it by moving LockedUntil into
+// the future, so each run happens on one instance.
+type JobRun struct {
+	Name         string    `json:"name" bson:"_id"`
+	Schedule     string    `json:"schedule" bson:"schedule"`
+	Owner        string    `json:"owner,omitempty" bson:"owner,omitempty"`
+	LockedUntil  time.Time `json:"locked_until" bson:"locked_until"`
+	NextRunAt    time.Time `json:"next_run_at" bson:"next_run_at"`
+	LastRunAt    time.Time `json:"last_run_at,omitempty" bson:"last_run_at,omitempty"`
+	LastDuration string    `json:"last_duration,omitempty" bson:"last_duration,omitempty"`
+	LastError    string    `json:"last_error,omitempty" bson:"last_error,omitempty"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Message is a message in the conversation between the parties of a hiring.
+// Redacted is set when phone numbers were hidden because the hiring was not
+// approved yet.
+type Message struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID    primitive.ObjectID `json:"hiring_id,omitempty" bson:"hiring_id,omitempty"`
+	SenderID    primitive.ObjectID `json:"sender_id,omitempty" bson:"sender_id,omitempty"`
+	SenderType  string             `json:"sender_type,omitempty" bson:"sender_type,omitempty"`
+	Body        string             `json:"body,omitempty" bson:"body,omitempty"`
+	Attachments []Attachment       `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	Redacted    bool               `json:"redacted,omitempty" bson:"redacted,omitempty"`
+	ReadAt      time.Time          `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// Attachment references a file uploaded elsewhere, such as a photo or ID scan
+type Attachment struct {
+	URL         string `json:"url" bson:"url" binding:"required,url"`
+	Name        string `json:"name,omitempty" bson:"name,omitempty" binding:"max=200"`
+	ContentType string `json:"content_type,omitempty" bson:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty" bson:"size,omitempty" binding:"min=0"`
+}
+
+// MessageRequest is the request body for sending a message
+type MessageRequest struct {
+	Body        string       `json:"body" binding:"max=4000"`
+	Attachments []Attachment `json:"attachments" binding:"max=5,dive"`
+}
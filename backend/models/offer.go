@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OfferStatus string
+
+const (
+	OfferOpen     OfferStatus = "OPEN"
+	OfferAccepted OfferStatus = "ACCEPTED"
+	OfferDeclined OfferStatus = "DECLINED"
+	// OfferSuperseded marks an open offer replaced by a newer proposal
+	OfferSuperseded OfferStatus = "SUPERSEDED"
+)
+
+// HiringTerms are the negotiable terms of a hiring
+type HiringTerms struct {
+	SalaryOffer    float64        `json:"salary_offer,omitempty" bson:"salary_offer,omitempty" binding:"omitempty,gt=0"`
+	StartDate      time.Time      `json:"start_date,omitempty" bson:"start_date,omitempty"`
+	EmploymentType EmploymentType `json:"employment_type,omitempty" bson:"employment_type,omitempty" binding:"omitempty,oneof=LIVE_OUT LIVE_IN"`
+	Requirements   string         `json:"requirements,omitempty" bson:"requirements,omitempty" binding:"max=2000"`
+}
+
+// Equal reports whether t and other propose the same terms. Start dates are
+// compared as instants, since == also compares their locations.
+func (t HiringTerms) Equal(other HiringTerms) bool {
+	return t.SalaryOffer == other.SalaryOffer &&
+		t.StartDate.Equal(other.StartDate) &&
+		t.EmploymentType == other.EmploymentType &&
+		t.Requirements == other.Requirements
+}
+
+// Offer is a proposal of terms for a pending hiring, made by either party.
+// Each proposal supersedes the open one before it, so only the latest
+// proposal can be accepted or declined.
+type Offer struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID    primitive.ObjectID `json:"hiring_id,omitempty" bson:"hiring_id,omitempty"`
+	ProposedBy  string             `json:"proposed_by,omitempty" bson:"proposed_by,omitempty"`
+	Terms       HiringTerms        `json:"terms" bson:"terms"`
+	Status      OfferStatus        `json:"status,omitempty" bson:"status,omitempty"`
+	RespondedAt time.Time          `json:"responded_at,omitempty" bson:"responded_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
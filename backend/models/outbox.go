@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending    OutboxStatus = "PENDING"
+	OutboxProcessing OutboxStatus = "PROCESSING"
+	OutboxPublished  OutboxStatus = "PUBLISHED"
+	OutboxFailed     OutboxStatus = "FAILED"
+)
+
+// OutboxEntry is a domain event written in the same transaction as the data
+// it describes, waiting to be relayed to asynchronous subscribers
+type OutboxEntry struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Event       string             `json:"event,omitempty" bson:"event,omitempty"`
+	Payload     bson.Raw           `json:"payload,omitempty" bson:"payload,omitempty"`
+	Status      OutboxStatus       `json:"status,omitempty" bson:"status,omitempty"`
+	Attempts    int                `json:"attempts" bson:"attempts"`
+	DeliveredTo []string           `json:"delivered_to,omitempty" bson:"delivered_to,omitempty"`
+	LastError   string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	AvailableAt time.Time          `json:"available_at,omitempty" bson:"available_at,omitempty"`
+	LockedUntil time.Time          `json:"locked_until,omitempty" bson:"locked_until,omitempty"`
+	CreatedAt   time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	PublishedAt time.Time          `json:"published_at,omitempty" bson:"published_at,omitempty"`
+}
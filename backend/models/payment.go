@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PaymentPurpose string
+
+const PlacementFeePayment PaymentPurpose = "PLACEMENT_FEE"
+
+type PaymentStatus string
+
+const (
+	// PaymentPending is waiting for the employer to pay at the provider
+	PaymentPending PaymentStatus = "PENDING"
+	// PaymentHeld is paid and held in escrow until the trial period passes
+	PaymentHeld     PaymentStatus = "HELD"
+	PaymentReleased PaymentStatus = "RELEASED"
+	// PaymentRefunding is a refund sent to the provider and not confirmed yet
+	PaymentRefunding PaymentStatus = "REFUNDING"
+	PaymentRefunded  PaymentStatus = "REFUNDED"
+	PaymentFailed    PaymentStatus = "FAILED"
+	// PaymentCancelled is a pending payment no longer needed because the
+	// hiring ended before it was paid
+	PaymentCancelled PaymentStatus = "CANCELLED"
+)
+
+// Payment is money collected from an employer through a payment provider.
+// Amounts are in Ethiopian birr.
+type Payment struct {
+	ID                primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID          primitive.ObjectID `json:"hiring_id" bson:"hiring_id"`
+	EmployerID        primitive.ObjectID `json:"employer_id" bson:"employer_id"`
+	Purpose           PaymentPurpose     `json:"purpose" bson:"purpose"`
+	InvoiceID         primitive.ObjectID `json:"invoice_id,omitempty" bson:"invoice_id,omitempty"`
+	Amount            float64            `json:"amount" bson:"amount"`
+	Currency          string             `json:"currency" bson:"currency"`
+	Provider          string             `json:"provider" bson:"provider"`
+	Reference         string             `json:"reference" bson:"reference"`
+	ProviderReference string             `json:"provider_reference,omitempty" bson:"provider_reference,omitempty"`
+	CheckoutURL       string             `json:"checkout_url,omitempty" bson:"checkout_url,omitempty"`
+	Status            PaymentStatus      `json:"status" bson:"status"`
+	TransferredFrom   primitive.ObjectID `json:"transferred_from,omitempty" bson:"transferred_from,omitempty"`
+	RefundReason      string             `json:"refund_reason,omitempty" bson:"refund_reason,omitempty"`
+	RefundedFrom      PaymentStatus      `json:"-" bson:"refunded_from,omitempty"`
+	RefundSentAt      time.Time          `json:"-" bson:"refund_sent_at,omitempty"`
+	PaidAt            time.Time          `json:"paid_at,omitempty" bson:"paid_at,omitempty"`
+	ReleasedAt        time.Time          `json:"released_at,omitempty" bson:"released_at,omitempty"`
+	RefundedAt        time.Time          `json:"refunded_at,omitempty" bson:"refunded_at,omitempty"`
+	CreatedAt         time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// PaymentWebhook is a provider callback that was processed. Its provider and
+// event ID are unique, so a callback delivered twice is applied once.
+type PaymentWebhook struct {
+	ID         primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Provider   string             `json:"provider" bson:"provider"`
+	EventID    string             `json:"event_id" bson:"event_id"`
+	Reference  string             `json:"reference" bson:"reference"`
+	Status     string             `json:"status" bson:"status"`
+	Payload    string             `json:"payload" bson:"payload"`
+	ReceivedAt time.Time          `json:"received_at" bson:"received_at"`
+}
+
+// RefundRequest is the request body for refunding a payment
+type RefundRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// LedgerTransaction is a balanced set of ledger postings: the debits equal
+// the credits. Key makes posting idempotent; a transaction with a key that
+// was posted before is not posted again. Amounts are in santim, a hundredth
+// of a birr.
+type LedgerTransaction struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Key       string             `json:"key" bson:"key"`
+	Memo      string             `json:"memo" bson:"memo"`
+	PaymentID primitive.ObjectID `json:"payment_id,omitempty" bson:"payment_id,omitempty"`
+	HiringID  primitive.ObjectID `json:"hiring_id,omitempty" bson:"hiring_id,omitempty"`
+	Postings  []Posting          `json:"postings" bson:"postings"`
+	Accounts  []string           `json:"-" bson:"accounts"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// Posting moves an amount into (debit) or out of (credit) an account
+type Posting struct {
+	Account string `json:"account" bson:"account"`
+	Debit   int64  `json:"debit,omitempty" bson:"debit,omitempty"`
+	Credit  int64  `json:"credit,omitempty" bson:"credit,omitempty"`
+}
+
+// LedgerAccount is the running balance of an account, debits minus credits,
+// in santim
+type LedgerAccount struct {
+	Name      string    `json:"name" bson:"_id"`
+	Balance   int64     `json:"balance" bson:"balance"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
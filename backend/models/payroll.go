@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SalaryStatus string
+
+const (
+	SalaryUnpaid SalaryStatus = "UNPAID"
+	SalaryPaid   SalaryStatus = "PAID"
+)
+
+type SalaryAdjustmentType string
+
+const (
+	// SalaryDeduction is taken off the pay of the period, such as for
+	// unpaid leave
+	SalaryDeduction SalaryAdjustmentType = "DEDUCTION"
+	// SalaryAdvance is money paid to the housekeeper early, taken off the
+	// pay of the period
+	SalaryAdvance SalaryAdjustmentType = "ADVANCE"
+)
+
+// SalaryAdjustment is an amount taken off the net pay of a period
+type SalaryAdjustment struct {
+	ID          primitive.ObjectID   `json:"id" bson:"_id"`
+	Type        SalaryAdjustmentType `json:"type" bson:"type"`
+	Description string               `json:"description" bson:"description"`
+	Amount      float64              `json:"amount" bson:"amount"`
+	CreatedBy   string               `json:"created_by" bson:"created_by"`
+	CreatedAt   time.Time            `json:"created_at" bson:"created_at"`
+}
+
+// SalaryRecord is the pay of a placed housekeeper for one calendar month,
+// such as "2026-10". The first month of a placement is prorated by day.
+// Amounts are in Ethiopian birr.
+type SalaryRecord struct {
+	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID      primitive.ObjectID `json:"hiring_id" bson:"hiring_id"`
+	EmployerID    primitive.ObjectID `json:"employer_id" bson:"employer_id"`
+	HousekeeperID primitive.ObjectID `json:"housekeeper_id" bson:"housekeeper_id"`
+	Period        string             `json:"period" bson:"period"`
+	PeriodStart   time.Time          `json:"period_start" bson:"period_start"`
+	PeriodEnd     time.Time          `json:"period_end" bson:"period_end"`
+	// MonthlySalary is the agreed salary; GrossSalary is what is earned in
+	// the period
+	MonthlySalary   float64            `json:"monthly_salary" bson:"monthly_salary"`
+	GrossSalary     float64            `json:"gross_salary" bson:"gross_salary"`
+	IncomeTax       float64            `json:"income_tax" bson:"income_tax"`
+	EmployeePension float64            `json:"employee_pension" bson:"employee_pension"`
+	EmployerPension float64            `json:"employer_pension" bson:"employer_pension"`
+	PensionEnrolled bool               `json:"pension_enrolled" bson:"pension_enrolled"`
+	Adjustments     []SalaryAdjustment `json:"adjustments" bson:"adjustments"`
+	NetPay          float64            `json:"net_pay" bson:"net_pay"`
+	Status          SalaryStatus       `json:"status" bson:"status"`
+	PaidBy          string             `json:"paid_by,omitempty" bson:"paid_by,omitempty"`
+	PaymentNote     string             `json:"payment_note,omitempty" bson:"payment_note,omitempty"`
+	PaidAt          time.Time          `json:"paid_at,omitempty" bson:"paid_at,omitempty"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// PayrollPeriodRequest is the request body for opening a payroll period
+type PayrollPeriodRequest struct {
+	Period string `json:"period" binding:"required,datetime=2006-01"`
+}
+
+// SalaryAdjustmentRequest is the request body for recording a deduction or
+// advance
+type SalaryAdjustmentRequest struct {
+	Type        SalaryAdjustmentType `json:"type" binding:"required,oneof=DEDUCTION ADVANCE"`
+	Description string               `json:"description" binding:"required,max=200"`
+	Amount      float64              `json:"amount" binding:"required,gt=0"`
+}
+
+// SalaryPaymentRequest is the request body for marking a salary paid
+type SalaryPaymentRequest struct {
+	Note string `json:"note,omitempty" binding:"max=500"`
+}
@@ -34,9 +34,44 @@ type ReviewsResponse struct {
 	Reviews []Review `json:"reviews"`
 }
 
+// RatingSummaryResponse represents the rating breakdown of a housekeeper
+type RatingSummaryResponse struct {
+	HousekeeperID string `json:"housekeeper_id"`
+	RatingSummary
+}
+
+// EmployerReviewsResponse represents an employer's rating and the reviews behind it
+type EmployerReviewsResponse struct {
+	EmployerID string           `json:"employer_id"`
+	Rating     float64          `json:"rating"`
+	Count      int              `json:"count"`
+	Reviews    []EmployerReview `json:"reviews"`
+}
+
+// ModerationQueueItem represents a review waiting for a moderator and its open reports
+type ModerationQueueItem struct {
+	Review  Review         `json:"review"`
+	Reports []ReviewReport `json:"reports"`
+}
+
+// EmployerModerationQueueItem represents a review of an employer waiting for
+// a moderator and its open reports
+type EmployerModerationQueueItem struct {
+	Review  EmployerReview `json:"review"`
+	Reports []ReviewReport `json:"reports"`
+}
+
 // LoginCredentials represents the login credentials
 type LoginCredentials struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
-	UserType string `json:"user_type" binding:"required"` // housekeeper or employer
+	UserType string `json:"user_type" binding:"required"` // housekeeper, employer or admin
+}
+
+// MessagesResponse represents a page of a hiring conversation, newest first.
+// Pass NextBefore as the before query parameter to fetch older messages.
+type MessagesResponse struct {
+	Messages   []Message `json:"messages"`
+	NextBefore string    `json:"next_before,omitempty"`
+	Unread     int64     `json:"unread"`
 }
@@ -6,11 +6,217 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Rating dimensions employers can score separately
+const (
+	Punctuality   = "punctuality"
+	Cleanliness   = "cleanliness"
+	Childcare     = "childcare"
+	Cooking       = "cooking"
+	Communication = "communication"
+)
+
+var RatingDimensions = []string{Punctuality, Cleanliness, Childcare, Cooking, Communication}
+
+type ReviewStatus string
+
+const (
+	// Reviews written before moderation existed have no status and are visible
+	ReviewVisible       ReviewStatus = "VISIBLE"
+	ReviewPendingReview ReviewStatus = "PENDING_REVIEW"
+	ReviewHidden        ReviewStatus = "HIDDEN"
+)
+
+// HiddenReviewStatuses are excluded from public listings and rating aggregates
+var HiddenReviewStatuses = []ReviewStatus{ReviewPendingReview, ReviewHidden}
+
+type ReportReason string
+
+const (
+	ReportSpam         ReportReason = "SPAM"
+	ReportAbusive      ReportReason = "ABUSIVE"
+	ReportPersonalInfo ReportReason = "PERSONAL_INFO"
+	ReportFake         ReportReason = "FAKE"
+	ReportOther        ReportReason = "OTHER"
+)
+
+// ReportTarget is what a report complains about
+type ReportTarget string
+
+const (
+	// Reports filed before targets existed have none and are about the review
+	ReportTargetReview         ReportTarget = "REVIEW"
+	ReportTargetReply          ReportTarget = "REPLY"
+	ReportTargetEmployerReview ReportTarget = "EMPLOYER_REVIEW"
+)
+
 type Review struct {
-	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	EmployerID    primitive.ObjectID `json:"employer_id,omitempty" bson:"employer_id,omitempty"`
-	HousekeeperID primitive.ObjectID `json:"housekeeper_id,omitempty" bson:"housekeeper_id,omitempty"`
-	Rating        float64            `json:"rating,omitempty" bson:"rating,omitempty" binding:"required,min=1,max=5"`
-	Comment       string             `json:"comment,omitempty" bson:"comment,omitempty"`
-	CreatedAt     time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	ID              primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID        primitive.ObjectID `json:"hiring_id,omitempty" bson:"hiring_id,omitempty" binding:"required"`
+	EmployerID      primitive.ObjectID `json:"employer_id,omitempty" bson:"employer_id,omitempty"`
+	HousekeeperID   primitive.ObjectID `json:"housekeeper_id,omitempty" bson:"housekeeper_id,omitempty"`
+	Rating          float64            `json:"rating,omitempty" bson:"rating,omitempty" binding:"required,min=1,max=5"`
+	Scores          *RatingScores      `json:"scores,omitempty" bson:"scores,omitempty"`
+	Comment         string             `json:"comment,omitempty" bson:"comment,omitempty"`
+	Verified        bool               `json:"verified" bson:"verified"`
+	Status          ReviewStatus       `json:"status,omitempty" bson:"status,omitempty"`
+	ModerationFlags []string           `json:"moderation_flags,omitempty" bson:"moderation_flags,omitempty"`
+	OpenReports     int                `json:"open_reports,omitempty" bson:"open_reports,omitempty"`
+	ModerationNote  string             `json:"moderation_note,omitempty" bson:"moderation_note,omitempty"`
+	ModeratedAt     time.Time          `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+	Reply           *ReviewReply       `json:"reply,omitempty" bson:"reply,omitempty"`
+	EditHistory     []ReviewRevision   `json:"edit_history,omitempty" bson:"edit_history,omitempty"`
+	CreatedAt       time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt       time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// ReviewReport is a user's complaint about a review
+type ReviewReport struct {
+	ID           primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ReviewID     primitive.ObjectID `json:"review_id,omitempty" bson:"review_id,omitempty"`
+	Target       ReportTarget       `json:"target,omitempty" bson:"target,omitempty" binding:"omitempty,oneof=REVIEW REPLY"`
+	ReporterID   primitive.ObjectID `json:"reporter_id,omitempty" bson:"reporter_id,omitempty"`
+	ReporterType string             `json:"reporter_type,omitempty" bson:"reporter_type,omitempty"`
+	Reason       ReportReason       `json:"reason,omitempty" bson:"reason,omitempty" binding:"required,oneof=SPAM ABUSIVE PERSONAL_INFO FAKE OTHER"`
+	Details      string             `json:"details,omitempty" bson:"details,omitempty"`
+	Resolved     bool               `json:"resolved" bson:"resolved"`
+	CreatedAt    time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// ModerationAction is the request body for hiding or restoring a review
+type ModerationAction struct {
+	Note string `json:"note,omitempty"`
+}
+
+// ReviewReply is the reviewed housekeeper's public answer to a review. It is
+// moderated separately from the review.
+type ReviewReply struct {
+	Comment         string       `json:"comment,omitempty" bson:"comment,omitempty" binding:"required"`
+	Status          ReviewStatus `json:"status,omitempty" bson:"status,omitempty"`
+	ModerationFlags []string     `json:"moderation_flags,omitempty" bson:"moderation_flags,omitempty"`
+	ModerationNote  string       `json:"moderation_note,omitempty" bson:"moderation_note,omitempty"`
+	ModeratedAt     time.Time    `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+	CreatedAt       time.Time    `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// ReviewRevision is a previous version of an edited review
+type ReviewRevision struct {
+	Rating   float64       `json:"rating,omitempty" bson:"rating,omitempty"`
+	Scores   *RatingScores `json:"scores,omitempty" bson:"scores,omitempty"`
+	Comment  string        `json:"comment,omitempty" bson:"comment,omitempty"`
+	EditedAt time.Time     `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+}
+
+// ReviewInput is the request body for reviewing a housekeeper. Everything
+// else on a review is set by the server.
+type ReviewInput struct {
+	HiringID      primitive.ObjectID `json:"hiring_id" binding:"required"`
+	HousekeeperID primitive.ObjectID `json:"housekeeper_id,omitempty"`
+	Rating        float64            `json:"rating" binding:"required,min=1,max=5"`
+	Scores        *RatingScores      `json:"scores,omitempty"`
+	Comment       string             `json:"comment,omitempty"`
+}
+
+// ReviewUpdate is the request body for editing a review
+type ReviewUpdate struct {
+	Rating  float64       `json:"rating,omitempty" binding:"omitempty,min=1,max=5"`
+	Scores  *RatingScores `json:"scores,omitempty"`
+	Comment *string       `json:"comment,omitempty"`
+}
+
+// RatingScores holds the optional per-dimension scores of a review. Dimensions
+// that do not apply to the placement are left out.
+type RatingScores struct {
+	Punctuality   float64 `json:"punctuality,omitempty" bson:"punctuality,omitempty" binding:"omitempty,min=1,max=5"`
+	Cleanliness   float64 `json:"cleanliness,omitempty" bson:"cleanliness,omitempty" binding:"omitempty,min=1,max=5"`
+	Childcare     float64 `json:"childcare,omitempty" bson:"childcare,omitempty" binding:"omitempty,min=1,max=5"`
+	Cooking       float64 `json:"cooking,omitempty" bson:"cooking,omitempty" binding:"omitempty,min=1,max=5"`
+	Communication float64 `json:"communication,omitempty" bson:"communication,omitempty" binding:"omitempty,min=1,max=5"`
+}
+
+// ByDimension returns the scores keyed by dimension name, leaving out
+// dimensions that were not scored
+func (s RatingScores) ByDimension() map[string]float64 {
+	scores := map[string]float64{
+		Punctuality:   s.Punctuality,
+		Cleanliness:   s.Cleanliness,
+		Childcare:     s.Childcare,
+		Cooking:       s.Cooking,
+		Communication: s.Communication,
+	}
+	for dimension, score := range scores {
+		if score == 0 {
+			delete(scores, dimension)
+		}
+	}
+	return scores
+}
+
+// IsHidden reports whether the review is excluded from listings and rating
+// aggregates
+func (r Review) IsHidden() bool {
+	return isHiddenStatus(r.Status)
+}
+
+// IsHidden reports whether the reply is left out of listings
+func (r ReviewReply) IsHidden() bool {
+	return isHiddenStatus(r.Status)
+}
+
+// IsHidden reports whether the review is excluded from listings and the
+// employer's rating
+func (r EmployerReview) IsHidden() bool {
+	return isHiddenStatus(r.Status)
+}
+
+func isHiddenStatus(status ReviewStatus) bool {
+	for _, hidden := range HiddenReviewStatuses {
+		if status == hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// DimensionRating is the aggregate of one rating dimension
+type DimensionRating struct {
+	Average float64 `json:"average" bson:"average"`
+	Count   int     `json:"count" bson:"count"`
+	Sum     float64 `json:"-" bson:"sum"`
+}
+
+// RatingSummary is the aggregate of the visible reviews of a housekeeper.
+// Distribution counts reviews by star, keyed "1" to "5".
+type RatingSummary struct {
+	Average      float64                    `json:"average" bson:"average"`
+	Count        int                        `json:"count" bson:"count"`
+	Sum          float64                    `json:"-" bson:"sum"`
+	Dimensions   map[string]DimensionRating `json:"dimensions" bson:"dimensions"`
+	Distribution map[string]int             `json:"distribution" bson:"distribution"`
+}
+
+// EmployerReview is a housekeeper's review of an employer they worked for
+type EmployerReview struct {
+	ID              primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	HiringID        primitive.ObjectID `json:"hiring_id,omitempty" bson:"hiring_id,omitempty" binding:"required"`
+	EmployerID      primitive.ObjectID `json:"employer_id,omitempty" bson:"employer_id,omitempty"`
+	HousekeeperID   primitive.ObjectID `json:"housekeeper_id,omitempty" bson:"housekeeper_id,omitempty"`
+	Rating          float64            `json:"rating,omitempty" bson:"rating,omitempty" binding:"required,min=1,max=5"`
+	Comment         string             `json:"comment,omitempty" bson:"comment,omitempty"`
+	Verified        bool               `json:"verified" bson:"verified"`
+	Status          ReviewStatus       `json:"status,omitempty" bson:"status,omitempty"`
+	ModerationFlags []string           `json:"moderation_flags,omitempty" bson:"moderation_flags,omitempty"`
+	OpenReports     int                `json:"open_reports,omitempty" bson:"open_reports,omitempty"`
+	ModerationNote  string             `json:"moderation_note,omitempty" bson:"moderation_note,omitempty"`
+	ModeratedAt     time.Time          `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+	EditHistory     []ReviewRevision   `json:"edit_history,omitempty" bson:"edit_history,omitempty"`
+	CreatedAt       time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt       time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// EmployerReviewInput is the request body for reviewing an employer
+type EmployerReviewInput struct {
+	HiringID   primitive.ObjectID `json:"hiring_id" binding:"required"`
+	EmployerID primitive.ObjectID `json:"employer_id,omitempty"`
+	Rating     float64            `json:"rating" binding:"required,min=1,max=5"`
+	Comment    string             `json:"comment,omitempty"`
 }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SMSStatus string
+
+const (
+	SMSSent    SMSStatus = "SENT"
+	SMSFailed  SMSStatus = "FAILED"
+	SMSSkipped SMSStatus = "SKIPPED"
+)
+
+// SMSLog records every text message sent, or attempted, to a user
+type SMSLog struct {
+	ID                primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID            primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	UserType          string             `json:"user_type,omitempty" bson:"user_type,omitempty"`
+	To                string             `json:"to,omitempty" bson:"to,omitempty"`
+	Event             string             `json:"event,omitempty" bson:"event,omitempty"`
+	Body              string             `json:"body,omitempty" bson:"body,omitempty"`
+	Encoding          string             `json:"encoding,omitempty" bson:"encoding,omitempty"`
+	Segments          int                `json:"segments,omitempty" bson:"segments,omitempty"`
+	Truncated         bool               `json:"truncated,omitempty" bson:"truncated,omitempty"`
+	Status            SMSStatus          `json:"status,omitempty" bson:"status,omitempty"`
+	ProviderMessageID string             `json:"provider_message_id,omitempty" bson:"provider_message_id,omitempty"`
+	Error             string             `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt         time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// SMSPreferences is the request body for opting in or out of SMS notifications
+type SMSPreferences struct {
+	OptIn bool `json:"opt_in"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TrialStatus string
+
+const (
+	TrialActive TrialStatus = "ACTIVE"
+	TrialPassed TrialStatus = "PASSED"
+	// TrialReplaced marks a placement the employer ended during the trial
+	// to get a replacement housekeeper
+	TrialReplaced TrialStatus = "REPLACED"
+)
+
+type ReplacementReason string
+
+const (
+	SkillsMismatch   ReplacementReason = "SKILLS_MISMATCH"
+	PoorReliability  ReplacementReason = "POOR_RELIABILITY"
+	Misconduct       ReplacementReason = "MISCONDUCT"
+	HousekeeperLeft  ReplacementReason = "HOUSEKEEPER_LEFT"
+	OtherReplacement ReplacementReason = "OTHER"
+)
+
+// ReplacementReasons lists the reasons an employer may give for a replacement
+var ReplacementReasons = []ReplacementReason{SkillsMismatch, PoorReliability, Misconduct, HousekeeperLeft, OtherReplacement}
+
+// Trial is the probation window at the start of an approved placement.
+// During it the employer may ask for a replacement housekeeper.
+type Trial struct {
+	Status      TrialStatus  `json:"status" bson:"status"`
+	StartsAt    time.Time    `json:"starts_at" bson:"starts_at"`
+	EndsAt      time.Time    `json:"ends_at" bson:"ends_at"`
+	Replacement *Replacement `json:"replacement,omitempty" bson:"replacement,omitempty"`
+}
+
+// Replacement records why a placement was replaced and the hiring that
+// replaces it
+type Replacement struct {
+	Reason      ReplacementReason  `json:"reason" bson:"reason"`
+	Note        string             `json:"note,omitempty" bson:"note,omitempty"`
+	HiringID    primitive.ObjectID `json:"hiring_id" bson:"hiring_id"`
+	RequestedBy string             `json:"requested_by" bson:"requested_by"`
+	RequestedAt time.Time          `json:"requested_at" bson:"requested_at"`
+}
+
+// ReplacementRequest is the request body for replacing a housekeeper during
+// the trial. The replacement starts on StartDate, or right away when it is
+// omitted, on the terms of the original placement.
+type ReplacementRequest struct {
+	Reason        ReplacementReason  `json:"reason" binding:"required"`
+	Note          string             `json:"note,omitempty" binding:"max=500"`
+	HousekeeperID primitive.ObjectID `json:"housekeeper_id" binding:"required"`
+	StartDate     time.Time          `json:"start_date,omitempty"`
+}
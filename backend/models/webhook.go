@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookSubscription is a partner endpoint that receives signed event payloads
+type WebhookSubscription struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	URL         string             `json:"url,omitempty" bson:"url,omitempty" binding:"required,url"`
+	Secret      string             `json:"secret,omitempty" bson:"secret,omitempty"`
+	EventTypes  []string           `json:"event_types,omitempty" bson:"event_types,omitempty" binding:"required,min=1"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Active      bool               `json:"active" bson:"active"`
+	CreatedAt   time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt   time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+type WebhookSubscriptionUpdate struct {
+	URL         string   `json:"url,omitempty" binding:"omitempty,url"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// WebhookDelivery is the log entry for one event sent to one subscription
+type WebhookDelivery struct {
+	ID             primitive.ObjectID    `json:"id,omitempty" bson:"_id,omitempty"`
+	SubscriptionID primitive.ObjectID    `json:"subscription_id,omitempty" bson:"subscription_id,omitempty"`
+	EventID        string                `json:"event_id,omitempty" bson:"event_id,omitempty"`
+	EventType      string                `json:"event_type,omitempty" bson:"event_type,omitempty"`
+	Payload        string                `json:"payload,omitempty" bson:"payload,omitempty"`
+	Status         WebhookDeliveryStatus `json:"status,omitempty" bson:"status,omitempty"`
+	Attempts       int                   `json:"attempts" bson:"attempts"`
+	ResponseStatus int                   `json:"response_status,omitempty" bson:"response_status,omitempty"`
+	Error          string                `json:"error,omitempty" bson:"error,omitempty"`
+	LastAttemptAt  time.Time             `json:"last_attempt_at,omitempty" bson:"last_attempt_at,omitempty"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at,omitempty" bson:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
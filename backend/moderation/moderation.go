@@ -0,0 +1,159 @@
+package moderation
+
+import (
+	"backend/search"
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Flags raised by the checks
+const (
+	FlagBannedWord  = "BANNED_WORD"
+	FlagPhoneNumber = "PHONE_NUMBER"
+)
+
+// defaultBannedWords is a small starter list in English and Amharic. Extend it
+// with MODERATION_BANNED_WORDS (comma separated) or MODERATION_WORDS_FILE (one
+// word or phrase per line).
+var defaultBannedWords = []string{
+	"fuck", "shit", "bitch", "bastard", "idiot", "stupid", "whore",
+	"ደደብ", "ደንቆሮ", "ሸርሙጣ", "ውሻ", "ባለጌ", "ጅል",
+}
+
+// phonePattern matches Ethiopian mobile numbers such as 0911234567,
+// +251 911 234 567 and 251-7-1234-5678, as well as other long digit runs
+// that look like phone numbers
+var phonePattern = regexp.MustCompile(`(?:^|[^\d])((?:\+?251[\s.-]?|0)?[79](?:[\s.-]?\d){8}|\+?\d(?:[\s.-]?\d){9,13})(?:$|[^\d])`)
+
+// Result describes why a text was flagged
+type Result struct {
+	Flags   []string `json:"flags,omitempty"`
+	Matches []string `json:"matches,omitempty"`
+}
+
+func (r Result) Flagged() bool {
+	return len(r.Flags) > 0
+}
+
+// Filter checks user-written text for banned words and personal information
+type Filter struct {
+	mu      sync.RWMutex
+	words   map[string]bool
+	phrases []string
+}
+
+func NewFilter(words []string) *Filter {
+	f := &Filter{}
+	f.SetWords(words)
+	return f
+}
+
+// Default is the filter used for reviews
+var Default = NewFilter(defaultBannedWords)
+
+// Init adds the banned words configured in the environment to the default filter
+func Init() error {
+	words := append([]string{}, defaultBannedWords...)
+
+	if extra := os.Getenv("MODERATION_BANNED_WORDS"); extra != "" {
+		words = append(words, strings.Split(extra, ",")...)
+	}
+
+	if path := os.Getenv("MODERATION_WORDS_FILE"); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			words = append(words, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	Default.SetWords(words)
+	return nil
+}
+
+// SetWords replaces the banned words. Single words are matched against whole
+// words of the text; entries with several words are matched as phrases.
+func (f *Filter) SetWords(words []string) {
+	single := map[string]bool{}
+	var phrases []string
+
+	for _, word := range words {
+		tokens := search.Tokenize(word)
+		switch {
+		case len(tokens) == 1:
+			single[tokens[0]] = true
+		case len(tokens) > 1:
+			phrases = append(phrases, strings.Join(tokens, " "))
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.words = single
+	f.phrases = phrases
+}
+
+// Check runs every check on text
+func (f *Filter) Check(text string) Result {
+	var result Result
+
+	f.mu.RLock()
+	tokens := search.Tokenize(text)
+	for _, token := range tokens {
+		if f.words[token] {
+			result.Matches = append(result.Matches, token)
+		}
+	}
+	normalized := " " + strings.Join(tokens, " ") + " "
+	for _, phrase := range f.phrases {
+		if strings.Contains(normalized, " "+phrase+" ") {
+			result.Matches = append(result.Matches, phrase)
+		}
+	}
+	f.mu.RUnlock()
+
+	if len(result.Matches) > 0 {
+		result.Flags = append(result.Flags, FlagBannedWord)
+	}
+
+	if phones := FindPhoneNumbers(text); len(phones) > 0 {
+		result.Flags = append(result.Flags, FlagPhoneNumber)
+		result.Matches = append(result.Matches, phones...)
+	}
+
+	return result
+}
+
+// Check runs the default filter on text
+func Check(text string) Result {
+	return Default.Check(text)
+}
+
+// FindPhoneNumbers returns the phone numbers written in text
+func FindPhoneNumbers(text string) []string {
+	var phones []string
+	for _, match := range phonePattern.FindAllStringSubmatch(text, -1) {
+		phones = append(phones, strings.TrimSpace(match[1]))
+	}
+	return phones
+}
+
+// RedactPhoneNumbers replaces the phone numbers written in text with a
+// placeholder
+func RedactPhoneNumbers(text string) string {
+	for _, phone := range FindPhoneNumbers(text) {
+		text = strings.ReplaceAll(text, phone, "[phone number hidden]")
+	}
+	return text
+}
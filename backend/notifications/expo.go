@@ -0,0 +1,143 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	expoSendURL     = "https://exp.host/--/api/v2/push/send"
+	expoReceiptsURL = "https://exp.host/--/api/v2/push/getReceipts"
+
+	// Limits documented by the Expo Push API
+	expoMaxMessagesPerRequest = 100
+	expoMaxReceiptsPerRequest = 1000
+)
+
+// ExpoPushSender sends notifications through the Expo Push API
+type ExpoPushSender struct {
+	AccessToken string
+	Client      *http.Client
+}
+
+func NewExpoPushSender(accessToken string) *ExpoPushSender {
+	return &ExpoPushSender{
+		AccessToken: accessToken,
+		Client:      &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type expoStatus struct {
+	Status  string `json:"status"`
+	ID      string `json:"id,omitempty"`
+	Message string `json:"message,omitempty"`
+	Details struct {
+		Error string `json:"error,omitempty"`
+	} `json:"details,omitempty"`
+}
+
+type expoError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *ExpoPushSender) Send(ctx context.Context, messages []PushMessage) ([]PushTicket, error) {
+	tickets := make([]PushTicket, 0, len(messages))
+
+	for start := 0; start < len(messages); start += expoMaxMessagesPerRequest {
+		end := start + expoMaxMessagesPerRequest
+		if end > len(messages) {
+			end = len(messages)
+		}
+
+		var response struct {
+			Data   []expoStatus `json:"data"`
+			Errors []expoError  `json:"errors"`
+		}
+		if err := s.post(ctx, expoSendURL, messages[start:end], &response); err != nil {
+			return tickets, err
+		}
+		if len(response.Errors) > 0 {
+			return tickets, fmt.Errorf("expo push: %s: %s", response.Errors[0].Code, response.Errors[0].Message)
+		}
+		if len(response.Data) != end-start {
+			return tickets, fmt.Errorf("expo push: expected %d tickets, got %d", end-start, len(response.Data))
+		}
+
+		for _, status := range response.Data {
+			tickets = append(tickets, PushTicket{
+				ID:      status.ID,
+				OK:      status.Status == "ok",
+				Message: status.Message,
+				Error:   status.Details.Error,
+			})
+		}
+	}
+
+	return tickets, nil
+}
+
+func (s *ExpoPushSender) GetReceipts(ctx context.Context, ticketIDs []string) (map[string]PushReceiptResult, error) {
+	receipts := make(map[string]PushReceiptResult, len(ticketIDs))
+
+	for start := 0; start < len(ticketIDs); start += expoMaxReceiptsPerRequest {
+		end := start + expoMaxReceiptsPerRequest
+		if end > len(ticketIDs) {
+			end = len(ticketIDs)
+		}
+
+		var response struct {
+			Data   map[string]expoStatus `json:"data"`
+			Errors []expoError           `json:"errors"`
+		}
+		if err := s.post(ctx, expoReceiptsURL, map[string][]string{"ids": ticketIDs[start:end]}, &response); err != nil {
+			return receipts, err
+		}
+		if len(response.Errors) > 0 {
+			return receipts, fmt.Errorf("expo receipts: %s: %s", response.Errors[0].Code, response.Errors[0].Message)
+		}
+
+		for id, status := range response.Data {
+			receipts[id] = PushReceiptResult{
+				OK:      status.Status == "ok",
+				Message: status.Message,
+				Error:   status.Details.Error,
+			}
+		}
+	}
+
+	return receipts, nil
+}
+
+func (s *ExpoPushSender) post(ctx context.Context, url string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if s.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.AccessToken)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("expo push: unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
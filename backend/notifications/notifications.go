@@ -0,0 +1,234 @@
+package notifications
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Push is the sender used to deliver push notifications
+var Push PushSender
+
+// SMS is the provider used to deliver text messages
+var SMS SMSProvider
+
+// receiptDelay is how long to wait before asking the gateway for receipts.
+// Expo recommends waiting at least 15 minutes after sending.
+const receiptDelay = 15 * time.Minute
+
+// Init selects the push sender from the PUSH_PROVIDER environment variable.
+// "stub" keeps messages in memory; anything else uses the Expo Push API.
+// Only the fake SMS provider is available for now.
+func Init() {
+	switch os.Getenv("PUSH_PROVIDER") {
+	case "stub":
+		Push = NewStubPushSender()
+	default:
+		Push = NewExpoPushSender(os.Getenv("EXPO_ACCESS_TOKEN"))
+	}
+
+	SMS = NewFakeSMSProvider()
+}
+
+// NotifyUser sends a push notification to every device registered by the user
+func NotifyUser(ctx context.Context, userID primitive.ObjectID, event, title, body string, data map[string]interface{}) error {
+	if Push == nil {
+		return ErrPushNotConfigured
+	}
+
+	cursor, err := config.DB.Collection("device_tokens").Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	var devices []models.DeviceToken
+	if err := cursor.All(ctx, &devices); err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	data["event"] = event
+
+	messages := make([]PushMessage, len(devices))
+	for i, device := range devices {
+		messages[i] = PushMessage{To: device.Token, Title: title, Body: body, Data: data, Sound: "default"}
+	}
+
+	tickets, err := Push.Send(ctx, messages)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var receipts []interface{}
+	for i, ticket := range tickets {
+		receipt := models.PushReceipt{
+			TicketID:  ticket.ID,
+			UserID:    userID,
+			Token:     devices[i].Token,
+			Event:     event,
+			Status:    models.PushReceiptPending,
+			CreatedAt: now,
+		}
+
+		if !ticket.OK {
+			receipt.Status = models.PushReceiptError
+			receipt.Error = ticket.Error
+			receipt.CheckedAt = now
+			if ticket.Error == DeviceNotRegistered {
+				removeToken(ctx, devices[i].Token)
+			}
+		}
+
+		receipts = append(receipts, receipt)
+	}
+
+	if len(receipts) > 0 {
+		if _, err := config.DB.Collection("push_receipts").InsertMany(ctx, receipts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckPushReceipts fetches receipts for pending tickets and removes tokens
+// the gateway reports as no longer registered
+func CheckPushReceipts(ctx context.Context) error {
+	if Push == nil {
+		return ErrPushNotConfigured
+	}
+
+	cursor, err := config.DB.Collection("push_receipts").Find(ctx, bson.M{
+		"status":     models.PushReceiptPending,
+		"created_at": bson.M{"$lte": time.Now().Add(-receiptDelay)},
+	})
+	if err != nil {
+		return err
+	}
+
+	var pending []models.PushReceipt
+	if err := cursor.All(ctx, &pending); err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(pending))
+	for i, receipt := range pending {
+		ids[i] = receipt.TicketID
+	}
+
+	results, err := Push.GetReceipts(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, receipt := range pending {
+		result, ok := results[receipt.TicketID]
+		if !ok {
+			// Receipts are not available yet
+			continue
+		}
+
+		status := models.PushReceiptOK
+		if !result.OK {
+			status = models.PushReceiptError
+			if result.Error == DeviceNotRegistered {
+				removeToken(ctx, receipt.Token)
+			}
+		}
+
+		_, err := config.DB.Collection("push_receipts").UpdateOne(ctx,
+			bson.M{"_id": receipt.ID},
+			bson.M{"$set": bson.M{"status": status, "error": result.Error, "checked_at": now}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func removeToken(ctx context.Context, token string) {
+	if _, err := config.DB.Collection("device_tokens").DeleteMany(ctx, bson.M{"token": token}); err != nil {
+		log.Printf("Error removing invalid device token: %v", err)
+	}
+}
+
+// userCollections maps the user types issued in JWTs to their collections
+var userCollections = map[string]string{
+	"housekeeper": "housekeepers",
+	"employer":    "employers",
+}
+
+// SendSMS texts a user who opted in to SMS notifications. Every attempt is
+// written to the sms_logs collection.
+func SendSMS(ctx context.Context, userID primitive.ObjectID, userType, event, body string) error {
+	if SMS == nil {
+		return ErrSMSNotConfigured
+	}
+
+	collection, ok := userCollections[userType]
+	if !ok {
+		return fmt.Errorf("unknown user type %q", userType)
+	}
+
+	var user struct {
+		PhoneNumber string `bson:"phone_number"`
+		SMSOptIn    bool   `bson:"sms_opt_in"`
+	}
+	if err := config.DB.Collection(collection).FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+		return err
+	}
+	if !user.SMSOptIn {
+		return nil
+	}
+
+	prepared := PrepareSMS(body)
+	entry := models.SMSLog{
+		UserID:    userID,
+		UserType:  userType,
+		To:        user.PhoneNumber,
+		Event:     event,
+		Body:      prepared.Body,
+		Encoding:  prepared.Encoding,
+		Segments:  prepared.Segments,
+		Truncated: prepared.Truncated,
+		Status:    models.SMSSent,
+		CreatedAt: time.Now(),
+	}
+
+	var sendErr error
+	if user.PhoneNumber == "" {
+		entry.Status = models.SMSSkipped
+		entry.Error = "no phone number"
+	} else {
+		entry.ProviderMessageID, sendErr = SMS.Send(ctx, user.PhoneNumber, prepared.Body)
+		if sendErr != nil {
+			entry.Status = models.SMSFailed
+			entry.Error = sendErr.Error()
+		}
+	}
+
+	if _, err := config.DB.Collection("sms_logs").InsertOne(ctx, entry); err != nil {
+		log.Printf("Error logging SMS delivery: %v", err)
+	}
+
+	return sendErr
+}
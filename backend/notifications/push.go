@@ -0,0 +1,47 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+)
+
+// Error codes reported by the push gateway in tickets and receipts
+const (
+	DeviceNotRegistered = "DeviceNotRegistered"
+	MessageTooBig       = "MessageTooBig"
+	MessageRateExceeded = "MessageRateExceeded"
+)
+
+var ErrPushNotConfigured = errors.New("push sender is not configured")
+
+// PushMessage is a single notification addressed to one device token
+type PushMessage struct {
+	To    string                 `json:"to"`
+	Title string                 `json:"title,omitempty"`
+	Body  string                 `json:"body,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+	Sound string                 `json:"sound,omitempty"`
+}
+
+// PushTicket is the gateway's immediate answer for one message. Messages that
+// were accepted carry an ID that can later be exchanged for a receipt.
+type PushTicket struct {
+	ID      string
+	OK      bool
+	Message string
+	Error   string
+}
+
+// PushReceiptResult is the final delivery outcome for a ticket
+type PushReceiptResult struct {
+	OK      bool
+	Message string
+	Error   string
+}
+
+// PushSender delivers push messages to mobile devices. Tickets are returned in
+// the same order as the messages.
+type PushSender interface {
+	Send(ctx context.Context, messages []PushMessage) ([]PushTicket, error)
+	GetReceipts(ctx context.Context, ticketIDs []string) (map[string]PushReceiptResult, error)
+}
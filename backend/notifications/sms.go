@@ -0,0 +1,148 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+)
+
+var ErrSMSNotConfigured = errors.New("sms provider is not configured")
+
+// SMSProvider sends text messages to phone numbers and returns the provider's
+// message ID
+type SMSProvider interface {
+	Send(ctx context.Context, to, body string) (string, error)
+}
+
+// FakeSMSProvider logs messages instead of sending them
+type FakeSMSProvider struct {
+	mu     sync.Mutex
+	Sent   []FakeSMS
+	nextID int
+}
+
+type FakeSMS struct {
+	To   string
+	Body string
+}
+
+func NewFakeSMSProvider() *FakeSMSProvider {
+	return &FakeSMSProvider{}
+}
+
+func (p *FakeSMSProvider) Send(ctx context.Context, to, body string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextID++
+	p.Sent = append(p.Sent, FakeSMS{To: to, Body: body})
+	log.Printf("SMS to %s: %s", to, body)
+
+	return "fake-" + strconv.Itoa(p.nextID), nil
+}
+
+const (
+	SMSEncodingGSM7 = "GSM-7"
+	SMSEncodingUCS2 = "UCS-2"
+
+	// maxSMSSegments caps how many parts a single notification may be split into
+	maxSMSSegments = 3
+)
+
+// gsm7Basic is the GSM 03.38 basic character set; gsm7Extended characters
+// need an escape and take two septets each
+const (
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extended = "^{}\\[~]|€\f"
+)
+
+// PreparedSMS is a message body fitted to the SMS length limits
+type PreparedSMS struct {
+	Body      string
+	Encoding  string
+	Segments  int
+	Truncated bool
+}
+
+// PrepareSMS picks the encoding for body and truncates it so that it fits in
+// maxSMSSegments concatenated parts. Amharic text needs UCS-2, which only
+// allows 70 characters per single message.
+func PrepareSMS(body string) PreparedSMS {
+	runes := []rune(body)
+
+	encoding := SMSEncodingGSM7
+	for _, r := range runes {
+		if !isGSM7(r) {
+			encoding = SMSEncodingUCS2
+			break
+		}
+	}
+
+	single, multi := 160, 153
+	if encoding == SMSEncodingUCS2 {
+		single, multi = 70, 67
+	}
+
+	length := smsLength(runes, encoding)
+	if length <= single {
+		return PreparedSMS{Body: body, Encoding: encoding, Segments: 1}
+	}
+
+	limit := multi * maxSMSSegments
+	truncated := false
+	if length > limit {
+		ellipsis := []rune("...")
+		for smsLength(runes, encoding)+len(ellipsis) > limit {
+			runes = runes[:len(runes)-1]
+		}
+		runes = append(runes, ellipsis...)
+		length = smsLength(runes, encoding)
+		truncated = true
+	}
+
+	return PreparedSMS{
+		Body:      string(runes),
+		Encoding:  encoding,
+		Segments:  (length + multi - 1) / multi,
+		Truncated: truncated,
+	}
+}
+
+func isGSM7(r rune) bool {
+	for _, c := range gsm7Basic + gsm7Extended {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
+
+func smsLength(runes []rune, encoding string) int {
+	if encoding == SMSEncodingUCS2 {
+		// Characters outside the basic multilingual plane use two code units
+		length := 0
+		for _, r := range runes {
+			if r > 0xFFFF {
+				length += 2
+			} else {
+				length++
+			}
+		}
+		return length
+	}
+
+	length := 0
+	for _, r := range runes {
+		length++
+		for _, c := range gsm7Extended {
+			if c == r {
+				length++
+				break
+			}
+		}
+	}
+	return length
+}
@@ -0,0 +1,51 @@
+package notifications
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// StubPushSender keeps messages in memory instead of sending them. It is used
+// for local development and tests. Tokens listed in Unregistered are answered
+// with a DeviceNotRegistered error, like the real gateway does.
+type StubPushSender struct {
+	mu           sync.Mutex
+	Sent         []PushMessage
+	Unregistered map[string]bool
+	nextID       int
+}
+
+func NewStubPushSender() *StubPushSender {
+	return &StubPushSender{Unregistered: map[string]bool{}}
+}
+
+func (s *StubPushSender) Send(ctx context.Context, messages []PushMessage) ([]PushTicket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tickets := make([]PushTicket, 0, len(messages))
+	for _, message := range messages {
+		if s.Unregistered[message.To] {
+			tickets = append(tickets, PushTicket{
+				Message: message.To + " is not a registered push notification recipient",
+				Error:   DeviceNotRegistered,
+			})
+			continue
+		}
+
+		s.nextID++
+		s.Sent = append(s.Sent, message)
+		tickets = append(tickets, PushTicket{ID: "stub-" + strconv.Itoa(s.nextID), OK: true})
+	}
+
+	return tickets, nil
+}
+
+func (s *StubPushSender) GetReceipts(ctx context.Context, ticketIDs []string) (map[string]PushReceiptResult, error) {
+	receipts := make(map[string]PushReceiptResult, len(ticketIDs))
+	for _, id := range ticketIDs {
+		receipts[id] = PushReceiptResult{OK: true}
+	}
+	return receipts, nil
+}
@@ -0,0 +1,72 @@
+package payments
+
+import (
+	"backend/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// FakePaymentProvider collects nothing. Its checkout URL points at the fake
+// pay endpoint, which reports the payment as paid, so the whole flow can be
+// exercised locally.
+type FakePaymentProvider struct {
+	// WebhookSecret signs callbacks with an HMAC-SHA256 of the body in
+	// FakeSignatureHeader. Without it every callback is rejected.
+	WebhookSecret string
+}
+
+func NewFakePaymentProvider(webhookSecret string) *FakePaymentProvider {
+	return &FakePaymentProvider{WebhookSecret: webhookSecret}
+}
+
+func (p *FakePaymentProvider) Name() string { return "fake" }
+
+func (p *FakePaymentProvider) Checkout(ctx context.Context, payment models.Payment, payer Payer) (string, error) {
+	return callbackURL("/payments/fake/" + payment.Reference + "/pay"), nil
+}
+
+func (p *FakePaymentProvider) Refund(ctx context.Context, payment models.Payment, amount float64, reason string) error {
+	log.Printf("Fake refund of %.2f %s for payment %s: %s", amount, payment.Currency, payment.Reference, reason)
+	return nil
+}
+
+// fakeWebhook is the body of a fake provider callback
+type fakeWebhook struct {
+	ID        string        `json:"id"`
+	Reference string        `json:"reference"`
+	Status    WebhookStatus `json:"status"`
+	Amount    float64       `json:"amount"`
+}
+
+func (p *FakePaymentProvider) ParseWebhook(header http.Header, body []byte) (WebhookEvent, error) {
+	if p.WebhookSecret == "" || !validHMAC(p.WebhookSecret, body, header.Get(FakeSignatureHeader)) {
+		return WebhookEvent{}, ErrInvalidSignature
+	}
+
+	var webhook fakeWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return WebhookEvent{}, err
+	}
+	return WebhookEvent{
+		ID:                webhook.ID,
+		Reference:         webhook.Reference,
+		ProviderReference: webhook.ID,
+		Status:            webhook.Status,
+		Amount:            webhook.Amount,
+	}, nil
+}
+
+// validHMAC reports whether signature is the hex HMAC-SHA256 of body
+func validHMAC(secret string, body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
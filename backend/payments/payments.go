@@ -0,0 +1,601 @@
+package payments
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/invoices"
+	"backend/ledger"
+	"backend/models"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrPaymentNotFound = errors.New("payment not found")
+	ErrNotRefundable   = errors.New("payment cannot be refunded")
+	ErrAmountMismatch  = errors.New("paid amount does not match the payment")
+	// ErrPaymentConflict is returned when a payment changed while it was
+	// being updated
+	ErrPaymentConflict = errors.New("payment was updated by someone else")
+	// ErrRefundPending is returned when the provider accepted a refund that
+	// could not be recorded yet; RetryRefunds records it later
+	ErrRefundPending = errors.New("refund was sent but not recorded yet")
+)
+
+// RequestPlacementFee asks the employer of an approved hiring to pay the
+// placement fee invoiced for it, issuing the invoice if it was not yet. A
+// replacement hiring takes over the payment of the placement it replaces
+// instead of being charged again. Calling it again for the same hiring does
+// not create a second payment. Without an active provider no payment is
+// created.
+func RequestPlacementFee(ctx context.Context, hiring models.Hiring) error {
+	if !hiring.ReplacementFor.IsZero() {
+		transferred, err := transferPlacementFee(ctx, hiring)
+		if err != nil || transferred {
+			return err
+		}
+	}
+
+	var payment models.Payment
+	err := config.DB.Collection("payments").FindOne(ctx, bson.M{
+		"hiring_id": hiring.ID,
+		"purpose":   models.PlacementFeePayment,
+	}).Decode(&payment)
+	if err == mongo.ErrNoDocuments {
+		if Active == nil {
+			return nil
+		}
+		invoice, err := invoices.Issue(ctx, hiring)
+		if err != nil {
+			return err
+		}
+		if invoice.Status != models.InvoiceUnpaid {
+			return nil
+		}
+
+		now := time.Now()
+		payment = models.Payment{
+			ID:         primitive.NewObjectID(),
+			HiringID:   hiring.ID,
+			EmployerID: hiring.EmployerID,
+			Purpose:    models.PlacementFeePayment,
+			InvoiceID:  invoice.ID,
+			Amount:     invoice.Total,
+			Currency:   invoice.Currency,
+			Provider:   Active.Name(),
+			Status:     models.PaymentPending,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		payment.Reference = "agz-" + payment.ID.Hex()
+		if _, err := config.DB.Collection("payments").InsertOne(ctx, payment); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				// Requested concurrently
+				return nil
+			}
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	// A checkout that failed before is started again when the request is
+	// retried
+	if payment.Status != models.PaymentPending || payment.CheckoutURL != "" {
+		return nil
+	}
+	return startCheckout(ctx, payment)
+}
+
+func startCheckout(ctx context.Context, payment models.Payment) error {
+	provider, err := Provider(payment.Provider)
+	if err != nil {
+		return err
+	}
+
+	var employer models.Employer
+	if err := config.DB.Collection("employers").FindOne(ctx, bson.M{"_id": payment.EmployerID}).Decode(&employer); err != nil {
+		return err
+	}
+
+	checkoutURL, err := provider.Checkout(ctx, payment, Payer{
+		Name:        employer.Name,
+		Email:       employer.Email,
+		PhoneNumber: employer.PhoneNumber,
+	})
+	if err != nil {
+		return err
+	}
+
+	return events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		payment.CheckoutURL = checkoutURL
+		payment.UpdatedAt = time.Now()
+		result, err := config.DB.Collection("payments").UpdateOne(uow.Context(),
+			bson.M{"_id": payment.ID, "status": models.PaymentPending, "checkout_url": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"checkout_url": payment.CheckoutURL, "updated_at": payment.UpdatedAt}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return nil
+		}
+		return uow.Publish(events.PaymentChanged{Payment: payment})
+	})
+}
+
+// transferPlacementFee moves the open placement fee of the placement hiring
+// replaces over to hiring. It reports false when there was nothing to move.
+func transferPlacementFee(ctx context.Context, hiring models.Hiring) (bool, error) {
+	var payment models.Payment
+	err := config.DB.Collection("payments").FindOne(ctx, bson.M{
+		"hiring_id": hiring.ReplacementFor,
+		"purpose":   models.PlacementFeePayment,
+		"status":    bson.M{"$in": []models.PaymentStatus{models.PaymentPending, models.PaymentHeld}},
+	}).Decode(&payment)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		payment.HiringID = hiring.ID
+		payment.TransferredFrom = hiring.ReplacementFor
+		payment.UpdatedAt = time.Now()
+		result, err := config.DB.Collection("payments").UpdateOne(uow.Context(),
+			bson.M{"_id": payment.ID, "hiring_id": hiring.ReplacementFor, "status": payment.Status},
+			bson.M{"$set": bson.M{
+				"hiring_id":        payment.HiringID,
+				"transferred_from": payment.TransferredFrom,
+				"updated_at":       payment.UpdatedAt,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrPaymentConflict
+		}
+
+		if payment.Status == models.PaymentHeld {
+			err := ledger.Post(uow.Context(), models.LedgerTransaction{
+				Key:       "transfer:" + payment.ID.Hex() + ":" + hiring.ID.Hex(),
+				Memo:      "Placement fee moved to replacement placement",
+				PaymentID: payment.ID,
+				HiringID:  hiring.ID,
+				Postings:  ledger.Entry(ledger.EscrowAccount(hiring.ReplacementFor), ledger.EscrowAccount(hiring.ID), ledger.Santim(payment.Amount)),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return uow.Publish(events.PaymentChanged{Payment: payment, PreviousStatus: payment.Status})
+	})
+	return err == nil, err
+}
+
+// HandleWebhook applies a verified provider callback. A callback that was
+// handled before is ignored, so providers can deliver it any number of times.
+func HandleWebhook(ctx context.Context, provider string, event WebhookEvent, payload []byte) error {
+	var refund *models.Payment
+	err := events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		count, err := config.DB.Collection("payment_webhooks").CountDocuments(uow.Context(),
+			bson.M{"provider": provider, "event_id": event.ID})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		var payment models.Payment
+		err = config.DB.Collection("payments").FindOne(uow.Context(),
+			bson.M{"provider": provider, "reference": event.Reference}).Decode(&payment)
+		if err == mongo.ErrNoDocuments {
+			return ErrPaymentNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		switch event.Status {
+		case WebhookSucceeded:
+			// A cancelled payment belongs to a hiring that ended while the
+			// employer was paying, so the money goes straight back
+			cancelled := payment.Status == models.PaymentCancelled
+			if err := markPaid(uow, &payment, event); err != nil {
+				return err
+			}
+			if cancelled {
+				refund = &payment
+			}
+		case WebhookFailed:
+			if err := markFailed(uow, &payment); err != nil {
+				return err
+			}
+		}
+
+		_, err = config.DB.Collection("payment_webhooks").InsertOne(uow.Context(), models.PaymentWebhook{
+			Provider:   provider,
+			EventID:    event.ID,
+			Reference:  event.Reference,
+			Status:     string(event.Status),
+			Payload:    string(payload),
+			ReceivedAt: time.Now(),
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if refund != nil {
+		if _, err := Refund(ctx, refund.ID, "The hiring ended before the payment was received"); err != nil {
+			log.Printf("Error refunding payment %s: %v", refund.Reference, err)
+		}
+	}
+	return nil
+}
+
+// markPaid puts a successful payment in escrow
+func markPaid(uow *events.UnitOfWork, payment *models.Payment, event WebhookEvent) error {
+	switch payment.Status {
+	case models.PaymentPending, models.PaymentFailed, models.PaymentCancelled:
+	default:
+		// Already paid
+		return nil
+	}
+	if ledger.Santim(event.Amount) != ledger.Santim(payment.Amount) {
+		return ErrAmountMismatch
+	}
+
+	previous := payment.Status
+	now := time.Now()
+	payment.Status = models.PaymentHeld
+	payment.ProviderReference = event.ProviderReference
+	payment.PaidAt = now
+	payment.UpdatedAt = now
+	result, err := config.DB.Collection("payments").UpdateOne(uow.Context(),
+		bson.M{"_id": payment.ID, "status": previous},
+		bson.M{"$set": bson.M{
+			"status":             payment.Status,
+			"provider_reference": payment.ProviderReference,
+			"paid_at":            payment.PaidAt,
+			"updated_at":         payment.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrPaymentConflict
+	}
+
+	err = ledger.Post(uow.Context(), models.LedgerTransaction{
+		Key:       "paid:" + payment.ID.Hex(),
+		Memo:      "Placement fee paid into escrow",
+		PaymentID: payment.ID,
+		HiringID:  payment.HiringID,
+		Postings:  ledger.Entry(ledger.ProviderAccount(payment.Provider), ledger.EscrowAccount(payment.HiringID), ledger.Santim(payment.Amount)),
+	})
+	if err != nil {
+		return err
+	}
+	return uow.Publish(events.PaymentChanged{Payment: *payment, PreviousStatus: previous})
+}
+
+func markFailed(uow *events.UnitOfWork, payment *models.Payment) error {
+	if payment.Status != models.PaymentPending {
+		return nil
+	}
+	return setStatus(uow, payment, models.PaymentFailed)
+}
+
+// setStatus moves payment on from its current status without touching the
+// ledger
+func setStatus(uow *events.UnitOfWork, payment *models.Payment, status models.PaymentStatus) error {
+	previous := payment.Status
+	payment.Status = status
+	payment.UpdatedAt = time.Now()
+	result, err := config.DB.Collection("payments").UpdateOne(uow.Context(),
+		bson.M{"_id": payment.ID, "status": previous},
+		bson.M{"$set": bson.M{"status": payment.Status, "updated_at": payment.UpdatedAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrPaymentConflict
+	}
+	return uow.Publish(events.PaymentChanged{Payment: *payment, PreviousStatus: previous})
+}
+
+// Release moves the placement fee held for a hiring out of escrow into
+// revenue once the trial period has passed
+func Release(ctx context.Context, hiringID primitive.ObjectID) error {
+	var payment models.Payment
+	err := config.DB.Collection("payments").FindOne(ctx, bson.M{
+		"hiring_id": hiringID,
+		"purpose":   models.PlacementFeePayment,
+		"status":    models.PaymentHeld,
+	}).Decode(&payment)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		now := time.Now()
+		payment.Status = models.PaymentReleased
+		payment.ReleasedAt = now
+		payment.UpdatedAt = now
+		result, err := config.DB.Collection("payments").UpdateOne(uow.Context(),
+			bson.M{"_id": payment.ID, "status": models.PaymentHeld},
+			bson.M{"$set": bson.M{"status": payment.Status, "released_at": now, "updated_at": now}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return nil
+		}
+
+		err = ledger.Post(uow.Context(), models.LedgerTransaction{
+			Key:       "release:" + payment.ID.Hex(),
+			Memo:      "Placement fee earned after the trial period",
+			PaymentID: payment.ID,
+			HiringID:  payment.HiringID,
+			Postings:  ledger.Entry(ledger.EscrowAccount(payment.HiringID), ledger.PlacementFeeRevenue, ledger.Santim(payment.Amount)),
+		})
+		if err != nil {
+			return err
+		}
+		return uow.Publish(events.PaymentChanged{Payment: payment, PreviousStatus: models.PaymentHeld})
+	})
+}
+
+// refundLease is how long a refund may stay REFUNDING before RetryRefunds
+// takes it over, long enough for a provider call to finish
+const refundLease = 10 * time.Minute
+
+// Refund returns a held or released payment to the employer through its
+// provider. The payment is claimed first so it cannot be refunded twice; if
+// the provider fails it goes back to its previous status. A refund left
+// REFUNDING, by a crash or a failed ledger post, is finished by RetryRefunds.
+func Refund(ctx context.Context, paymentID primitive.ObjectID, reason string) (models.Payment, error) {
+	var payment models.Payment
+	err := config.DB.Collection("payments").FindOne(ctx, bson.M{"_id": paymentID}).Decode(&payment)
+	if err == mongo.ErrNoDocuments {
+		return payment, ErrPaymentNotFound
+	}
+	if err != nil {
+		return payment, err
+	}
+	if payment.Status != models.PaymentHeld && payment.Status != models.PaymentReleased {
+		return payment, ErrNotRefundable
+	}
+
+	provider, err := Provider(payment.Provider)
+	if err != nil {
+		return payment, err
+	}
+
+	previous := payment.Status
+	result, err := config.DB.Collection("payments").UpdateOne(ctx,
+		bson.M{"_id": payment.ID, "status": previous},
+		bson.M{"$set": bson.M{
+			"status":        models.PaymentRefunding,
+			"refunded_from": previous,
+			"refund_reason": reason,
+			"updated_at":    time.Now(),
+		}},
+	)
+	if err != nil {
+		return payment, err
+	}
+	if result.MatchedCount == 0 {
+		return payment, ErrPaymentConflict
+	}
+	payment.Status = models.PaymentRefunding
+	payment.RefundedFrom = previous
+	payment.RefundReason = reason
+
+	if err := provider.Refund(ctx, payment, payment.Amount, reason); err != nil {
+		_, restoreErr := config.DB.Collection("payments").UpdateOne(ctx,
+			bson.M{"_id": payment.ID, "status": models.PaymentRefunding},
+			bson.M{
+				"$set":   bson.M{"status": previous, "updated_at": time.Now()},
+				"$unset": bson.M{"refunded_from": "", "refund_reason": ""},
+			},
+		)
+		if restoreErr != nil {
+			log.Printf("Error restoring payment %s after a failed refund: %v", payment.Reference, restoreErr)
+		}
+		return payment, err
+	}
+
+	if err := finishRefund(ctx, &payment); err != nil {
+		log.Printf("Error recording the refund of payment %s, it will be retried: %v", payment.Reference, err)
+		return payment, ErrRefundPending
+	}
+	return payment, nil
+}
+
+// RetryRefunds finishes the refunds left REFUNDING for longer than
+// refundLease. A refund the provider never confirmed is sent again; both
+// providers receive a refund reference derived from the payment reference,
+// so they do not pay it out twice. One that was confirmed is only recorded.
+func RetryRefunds(ctx context.Context) error {
+	collection := config.DB.Collection("payments")
+
+	for {
+		now := time.Now()
+		var payment models.Payment
+		err := collection.FindOneAndUpdate(ctx,
+			bson.M{"status": models.PaymentRefunding, "updated_at": bson.M{"$lte": now.Add(-refundLease)}},
+			bson.M{"$set": bson.M{"updated_at": now}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&payment)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := retryRefund(ctx, &payment); err != nil {
+			log.Printf("Error retrying the refund of payment %s: %v", payment.Reference, err)
+		}
+	}
+}
+
+func retryRefund(ctx context.Context, payment *models.Payment) error {
+	if payment.RefundSentAt.IsZero() {
+		provider, err := Provider(payment.Provider)
+		if err != nil {
+			return err
+		}
+		if err := provider.Refund(ctx, *payment, payment.Amount, payment.RefundReason); err != nil {
+			return err
+		}
+	}
+	return finishRefund(ctx, payment)
+}
+
+// finishRefund records that the provider accepted the refund of a REFUNDING
+// payment and moves the money out of escrow, or out of revenue if it was
+// earned already
+func finishRefund(ctx context.Context, payment *models.Payment) error {
+	if payment.RefundSentAt.IsZero() {
+		now := time.Now()
+		_, err := config.DB.Collection("payments").UpdateOne(ctx,
+			bson.M{"_id": payment.ID, "status": models.PaymentRefunding},
+			bson.M{"$set": bson.M{"refund_sent_at": now, "updated_at": now}},
+		)
+		if err != nil {
+			return err
+		}
+		payment.RefundSentAt = now
+	}
+
+	previous := payment.RefundedFrom
+	source := ledger.EscrowAccount(payment.HiringID)
+	if previous == models.PaymentReleased {
+		source = ledger.PlacementFeeRevenue
+	}
+
+	return events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		now := time.Now()
+		result, err := config.DB.Collection("payments").UpdateOne(uow.Context(),
+			bson.M{"_id": payment.ID, "status": models.PaymentRefunding},
+			bson.M{"$set": bson.M{"status": models.PaymentRefunded, "refunded_at": now, "updated_at": now}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrPaymentConflict
+		}
+		payment.Status = models.PaymentRefunded
+		payment.RefundedAt = now
+		payment.UpdatedAt = now
+
+		err = ledger.Post(uow.Context(), models.LedgerTransaction{
+			Key:       "refund:" + payment.ID.Hex(),
+			Memo:      "Placement fee refunded: " + payment.RefundReason,
+			PaymentID: payment.ID,
+			HiringID:  payment.HiringID,
+			Postings:  ledger.Entry(source, ledger.ProviderAccount(payment.Provider), ledger.Santim(payment.Amount)),
+		})
+		if err != nil {
+			return err
+		}
+		return uow.Publish(events.PaymentChanged{Payment: *payment, PreviousStatus: previous})
+	})
+}
+
+// SettleEndedHiring settles the open placement fee of a hiring that was
+// cancelled, completed or terminated, as endedFeeStatus decides
+func SettleEndedHiring(ctx context.Context, hiring models.Hiring) error {
+	cursor, err := config.DB.Collection("payments").Find(ctx, bson.M{
+		"hiring_id": hiring.ID,
+		"status":    bson.M{"$in": []models.PaymentStatus{models.PaymentPending, models.PaymentHeld}},
+	})
+	if err != nil {
+		return err
+	}
+	var payments []models.Payment
+	if err := cursor.All(ctx, &payments); err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		switch endedFeeStatus(hiring, payment.Status, time.Now()) {
+		case models.PaymentCancelled:
+			err = events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+				return setStatus(uow, &payment, models.PaymentCancelled)
+			})
+		case models.PaymentRefunded:
+			reason := "The hiring was cancelled"
+			if hiring.Status != models.Cancelled {
+				reason = "The placement ended during its trial period"
+			}
+			_, err = Refund(ctx, payment.ID, reason)
+		case models.PaymentReleased:
+			err = Release(ctx, hiring.ID)
+		}
+		if err != nil && err != ErrPaymentConflict && err != ErrRefundPending {
+			return err
+		}
+	}
+	return nil
+}
+
+// endedFeeStatus returns the status a placement fee in status moves to when
+// hiring ends, or status itself when it stays as it is. A placement replaced
+// during its trial keeps its fee for the replacement. Otherwise a fee that was
+// not paid yet is dropped unless the placement was completed, and a held fee
+// is refunded when the placement was cancelled or ended during its trial and
+// released when it ended after the trial. A placement that ended without a
+// recorded end is taken to have ended at now.
+func endedFeeStatus(hiring models.Hiring, status models.PaymentStatus, now time.Time) models.PaymentStatus {
+	trial := hiring.Trial
+	if hiring.Status == models.Terminated && trial != nil && trial.Replacement != nil {
+		return status
+	}
+	if status == models.PaymentPending {
+		if hiring.Status == models.Cancelled || hiring.Status == models.Terminated {
+			return models.PaymentCancelled
+		}
+		return status
+	}
+	if status != models.PaymentHeld {
+		return status
+	}
+
+	switch hiring.Status {
+	case models.Cancelled:
+		return models.PaymentRefunded
+	case models.Completed, models.Terminated:
+		ended := hiring.EndedAt
+		if ended.IsZero() {
+			ended = now
+		}
+		if trial != nil && trial.Status != models.TrialPassed && ended.Before(trial.EndsAt) {
+			return models.PaymentRefunded
+		}
+		return models.PaymentReleased
+	}
+	return status
+}
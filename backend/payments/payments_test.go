@@ -0,0 +1,45 @@
+package payments
+
+import (
+	"backend/models"
+	"testing"
+	"time"
+)
+
+func TestEndedFeeStatus(t *testing.T) {
+	trialEnd := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	during := trialEnd.AddDate(0, 0, -5)
+	after := trialEnd.AddDate(0, 1, 0)
+	active := &models.Trial{Status: models.TrialActive, EndsAt: trialEnd}
+	passed := &models.Trial{Status: models.TrialPassed, EndsAt: trialEnd}
+	replaced := &models.Trial{Status: models.TrialReplaced, EndsAt: trialEnd, Replacement: &models.Replacement{}}
+
+	tests := []struct {
+		name   string
+		hiring models.Hiring
+		status models.PaymentStatus
+		now    time.Time
+		want   models.PaymentStatus
+	}{
+		{"cancelled, unpaid", models.Hiring{Status: models.Cancelled}, models.PaymentPending, during, models.PaymentCancelled},
+		{"cancelled, held", models.Hiring{Status: models.Cancelled, Trial: active}, models.PaymentHeld, during, models.PaymentRefunded},
+		{"completed, unpaid", models.Hiring{Status: models.Completed, Trial: passed, EndedAt: after}, models.PaymentPending, after, models.PaymentPending},
+		{"completed during the trial", models.Hiring{Status: models.Completed, Trial: active, EndedAt: during}, models.PaymentHeld, after, models.PaymentRefunded},
+		{"completed after the trial", models.Hiring{Status: models.Completed, Trial: active, EndedAt: after}, models.PaymentHeld, after, models.PaymentReleased},
+		{"completed after a passed trial", models.Hiring{Status: models.Completed, Trial: passed, EndedAt: after}, models.PaymentHeld, after, models.PaymentReleased},
+		{"completed without a trial", models.Hiring{Status: models.Completed, EndedAt: during}, models.PaymentHeld, during, models.PaymentReleased},
+		{"completed without an end date", models.Hiring{Status: models.Completed, Trial: active}, models.PaymentHeld, during, models.PaymentRefunded},
+		{"terminated, unpaid", models.Hiring{Status: models.Terminated, Trial: active, EndedAt: during}, models.PaymentPending, during, models.PaymentCancelled},
+		{"terminated during the trial", models.Hiring{Status: models.Terminated, Trial: active, EndedAt: during}, models.PaymentHeld, after, models.PaymentRefunded},
+		{"terminated after the trial", models.Hiring{Status: models.Terminated, Trial: passed, EndedAt: after}, models.PaymentHeld, after, models.PaymentReleased},
+		{"replaced, unpaid", models.Hiring{Status: models.Terminated, Trial: replaced, EndedAt: during}, models.PaymentPending, during, models.PaymentPending},
+		{"replaced, held", models.Hiring{Status: models.Terminated, Trial: replaced, EndedAt: during}, models.PaymentHeld, during, models.PaymentHeld},
+		{"already released", models.Hiring{Status: models.Completed, Trial: passed, EndedAt: after}, models.PaymentReleased, after, models.PaymentReleased},
+	}
+
+	for _, test := range tests {
+		if got := endedFeeStatus(test.hiring, test.status, test.now); got != test.want {
+			t.Errorf("%s: endedFeeStatus = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
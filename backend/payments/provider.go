@@ -0,0 +1,126 @@
+package payments
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrUnknownProvider  = errors.New("unknown payment provider")
+)
+
+// Payer is who a checkout is for
+type Payer struct {
+	Name        string
+	Email       string
+	PhoneNumber string
+}
+
+type WebhookStatus string
+
+const (
+	WebhookSucceeded WebhookStatus = "succeeded"
+	WebhookFailed    WebhookStatus = "failed"
+)
+
+// WebhookEvent is a provider callback about a payment. ID identifies the
+// callback itself, so redeliveries can be recognised.
+type WebhookEvent struct {
+	ID                string
+	Reference         string
+	ProviderReference string
+	Status            WebhookStatus
+	Amount            float64
+}
+
+// PaymentProvider collects and refunds payments through a payment gateway
+type PaymentProvider interface {
+	Name() string
+	// Checkout starts collecting payment and returns the URL where the payer
+	// completes it. The outcome is reported to the webhook endpoint.
+	Checkout(ctx context.Context, payment models.Payment, payer Payer) (string, error)
+	// Refund returns amount birr of payment to the payer
+	Refund(ctx context.Context, payment models.Payment, amount float64, reason string) error
+	// ParseWebhook verifies the signature of a callback and decodes it
+	ParseWebhook(header http.Header, body []byte) (WebhookEvent, error)
+}
+
+// Active is the provider new payments are collected with
+var Active PaymentProvider
+
+var providers = map[string]PaymentProvider{}
+
+// Init registers the configured providers and selects the active one from
+// PAYMENT_PROVIDER. Chapa and Telebirr are registered when their credentials
+// are set. The fake provider collects nothing, so it is only registered when
+// FAKE_PAYMENTS_ENABLED is true and gin is not in release mode; it is then the
+// default. Without PAYMENT_PROVIDER and the fake provider, Active stays nil and
+// placement fees are not collected. Init fails when the named provider is not
+// registered.
+func Init() error {
+	if os.Getenv("FAKE_PAYMENTS_ENABLED") == "true" && gin.Mode() != gin.ReleaseMode {
+		register(NewFakePaymentProvider(os.Getenv("FAKE_PAYMENT_WEBHOOK_SECRET")))
+	}
+	if key := os.Getenv("CHAPA_SECRET_KEY"); key != "" {
+		register(NewChapaProvider(key, os.Getenv("CHAPA_WEBHOOK_SECRET")))
+	}
+	if appID := os.Getenv("TELEBIRR_APP_ID"); appID != "" {
+		register(NewTelebirrProvider(TelebirrConfig{
+			BaseURL:         os.Getenv("TELEBIRR_BASE_URL"),
+			WebCheckoutURL:  os.Getenv("TELEBIRR_WEB_CHECKOUT_URL"),
+			FabricAppID:     os.Getenv("TELEBIRR_FABRIC_APP_ID"),
+			AppSecret:       os.Getenv("TELEBIRR_APP_SECRET"),
+			MerchantAppID:   appID,
+			MerchantCode:    os.Getenv("TELEBIRR_MERCHANT_CODE"),
+			PrivateKeyPEM:   os.Getenv("TELEBIRR_PRIVATE_KEY"),
+			PublicKeyPEM:    os.Getenv("TELEBIRR_PUBLIC_KEY"),
+			NotifyURL:       callbackURL("/payments/webhooks/telebirr"),
+			RedirectBaseURL: os.Getenv("PAYMENT_RETURN_URL"),
+		}))
+	}
+
+	name := os.Getenv("PAYMENT_PROVIDER")
+	if name == "" {
+		if _, ok := providers["fake"]; !ok {
+			log.Println("No payment provider configured; placement fees will not be collected")
+			return nil
+		}
+		name = "fake"
+	}
+	provider, ok := providers[name]
+	if !ok {
+		return fmt.Errorf("payment provider %q is not configured; set PAYMENT_PROVIDER and its credentials", name)
+	}
+	Active = provider
+	return nil
+}
+
+func register(provider PaymentProvider) {
+	providers[provider.Name()] = provider
+}
+
+// Provider returns the registered provider called name
+func Provider(name string) (PaymentProvider, error) {
+	provider, ok := providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// callbackURL is the public URL of an API path, based on API_BASE_URL
+func callbackURL(path string) string {
+	base := os.Getenv("API_BASE_URL")
+	if base == "" {
+		base = "http://localhost:8080/api/v1"
+	}
+	return base + path
+}
@@ -0,0 +1,312 @@
+package payments
+
+import (
+	"backend/models"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TelebirrConfig holds the credentials Ethio Telecom issues to a Telebirr
+// merchant. The keys are PEM encoded; the public key is Telebirr's, used to
+// verify callbacks.
+type TelebirrConfig struct {
+	BaseURL         string
+	WebCheckoutURL  string
+	FabricAppID     string
+	AppSecret       string
+	MerchantAppID   string
+	MerchantCode    string
+	PrivateKeyPEM   string
+	PublicKeyPEM    string
+	NotifyURL       string
+	RedirectBaseURL string
+}
+
+// TelebirrProvider collects payments through the Telebirr H5 web checkout.
+// Requests are signed with the merchant's RSA key (SHA256WithRSA, PSS
+// padding) and callbacks are verified with Telebirr's public key.
+type TelebirrProvider struct {
+	Config TelebirrConfig
+	Client *http.Client
+}
+
+func NewTelebirrProvider(config TelebirrConfig) *TelebirrProvider {
+	return &TelebirrProvider{
+		Config: config,
+		Client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *TelebirrProvider) Name() string { return "telebirr" }
+
+func (p *TelebirrProvider) Checkout(ctx context.Context, payment models.Payment, payer Payer) (string, error) {
+	token, err := p.fabricToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	biz := map[string]string{
+		"notify_url":      p.Config.NotifyURL,
+		"appid":           p.Config.MerchantAppID,
+		"merch_code":      p.Config.MerchantCode,
+		"merch_order_id":  payment.Reference,
+		"trade_type":      "Checkout",
+		"title":           "AGAZH placement fee",
+		"total_amount":    strconv.FormatFloat(payment.Amount, 'f', 2, 64),
+		"trans_currency":  payment.Currency,
+		"timeout_express": "120m",
+		"business_type":   "BuyGoods",
+	}
+	if p.Config.RedirectBaseURL != "" {
+		biz["redirect_url"] = p.Config.RedirectBaseURL
+	}
+
+	var response struct {
+		Result     string `json:"result"`
+		Message    string `json:"msg"`
+		BizContent struct {
+			PrepayID string `json:"prepay_id"`
+		} `json:"biz_content"`
+	}
+	if err := p.call(ctx, token, "/payment/v1/merchant/preOrder", "payment.preorder", biz, &response); err != nil {
+		return "", err
+	}
+	if response.Result != "SUCCESS" || response.BizContent.PrepayID == "" {
+		return "", fmt.Errorf("telebirr: pre-order failed: %s", response.Message)
+	}
+
+	raw := map[string]string{
+		"appid":      p.Config.MerchantAppID,
+		"merch_code": p.Config.MerchantCode,
+		"nonce_str":  nonce(),
+		"prepay_id":  response.BizContent.PrepayID,
+		"timestamp":  strconv.FormatInt(time.Now().Unix(), 10),
+	}
+	sign, err := p.sign(raw)
+	if err != nil {
+		return "", err
+	}
+
+	query := signingString(raw) + "&sign=" + url.QueryEscape(sign) + "&sign_type=SHA256WithRSA"
+	return p.Config.WebCheckoutURL + "?" + query + "&version=1.0&trade_type=Checkout", nil
+}
+
+func (p *TelebirrProvider) Refund(ctx context.Context, payment models.Payment, amount float64, reason string) error {
+	token, err := p.fabricToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Result  string `json:"result"`
+		Message string `json:"msg"`
+	}
+	err = p.call(ctx, token, "/payment/v1/merchant/refund", "payment.refund", map[string]string{
+		"appid":             p.Config.MerchantAppID,
+		"merch_code":        p.Config.MerchantCode,
+		"merch_order_id":    payment.Reference,
+		"refund_request_no": payment.Reference + "R",
+		"refund_reason":     reason,
+		"refund_amount":     strconv.FormatFloat(amount, 'f', 2, 64),
+		"trans_currency":    payment.Currency,
+	}, &response)
+	if err != nil {
+		return err
+	}
+	if response.Result != "SUCCESS" {
+		return fmt.Errorf("telebirr: refund failed: %s", response.Message)
+	}
+	return nil
+}
+
+func (p *TelebirrProvider) ParseWebhook(header http.Header, body []byte) (WebhookEvent, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return WebhookEvent{}, err
+	}
+
+	values := map[string]string{}
+	for key, value := range fields {
+		if key != "sign" && key != "sign_type" {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	signature, _ := fields["sign"].(string)
+	if err := p.verify(values, signature); err != nil {
+		return WebhookEvent{}, ErrInvalidSignature
+	}
+
+	amount, err := strconv.ParseFloat(values["total_amount"], 64)
+	if err != nil {
+		return WebhookEvent{}, fmt.Errorf("telebirr: invalid amount %q", values["total_amount"])
+	}
+
+	status := WebhookFailed
+	if values["trade_status"] == "Completed" {
+		status = WebhookSucceeded
+	}
+	return WebhookEvent{
+		ID:                values["payment_order_id"] + ":" + values["trade_status"],
+		Reference:         values["merch_order_id"],
+		ProviderReference: values["trans_id"],
+		Status:            status,
+		Amount:            amount,
+	}, nil
+}
+
+func (p *TelebirrProvider) fabricToken(ctx context.Context) (string, error) {
+	var response struct {
+		Token string `json:"token"`
+	}
+	err := p.post(ctx, "/payment/v1/token", "", map[string]string{"appSecret": p.Config.AppSecret}, &response)
+	if err != nil {
+		return "", err
+	}
+	if response.Token == "" {
+		return "", errors.New("telebirr: no fabric token in response")
+	}
+	return response.Token, nil
+}
+
+// call sends a signed merchant API request
+func (p *TelebirrProvider) call(ctx context.Context, token, path, method string, biz map[string]string, out interface{}) error {
+	request := map[string]string{
+		"timestamp": strconv.FormatInt(time.Now().Unix(), 10),
+		"nonce_str": nonce(),
+		"method":    method,
+		"version":   "1.0",
+	}
+
+	// The signature covers the request fields and the business content
+	// fields together
+	signed := map[string]string{}
+	for key, value := range request {
+		signed[key] = value
+	}
+	for key, value := range biz {
+		signed[key] = value
+	}
+	sign, err := p.sign(signed)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{"biz_content": biz, "sign_type": "SHA256WithRSA", "sign": sign}
+	for key, value := range request {
+		payload[key] = value
+	}
+	return p.post(ctx, path, token, payload, out)
+}
+
+func (p *TelebirrProvider) post(ctx context.Context, path, token string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Config.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-APP-Key", p.Config.FabricAppID)
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("telebirr: unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (p *TelebirrProvider) sign(values map[string]string) (string, error) {
+	block, _ := pem.Decode([]byte(p.Config.PrivateKeyPEM))
+	if block == nil {
+		return "", errors.New("telebirr: invalid private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return "", err
+		}
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", errors.New("telebirr: private key is not an RSA key")
+	}
+
+	digest := sha256.Sum256([]byte(signingString(values)))
+	signature, err := rsa.SignPSS(rand.Reader, key, crypto.SHA256, digest[:], nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func (p *TelebirrProvider) verify(values map[string]string, signature string) error {
+	block, _ := pem.Decode([]byte(p.Config.PublicKeyPEM))
+	if block == nil {
+		return errors.New("telebirr: invalid public key")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("telebirr: public key is not an RSA key")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(signingString(values)))
+	return rsa.VerifyPSS(key, crypto.SHA256, digest[:], decoded, nil)
+}
+
+// signingString joins values as key=value pairs sorted by key, the form
+// Telebirr signs
+func signingString(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + values[key]
+	}
+	return strings.Join(pairs, "&")
+}
+
+func nonce() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
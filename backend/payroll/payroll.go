@@ -0,0 +1,298 @@
+package payroll
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const periodLayout = "2006-01"
+
+var (
+	ErrNotPlaced          = errors.New("hiring is not a monthly placement")
+	ErrPeriodOutOfRange   = errors.New("period is outside the placement or in the future")
+	ErrPeriodOpen         = errors.New("payroll period is already open")
+	ErrPeriodNotFound     = errors.New("payroll period not found")
+	ErrAdjustmentNotFound = errors.New("salary adjustment not found")
+	ErrAlreadyPaid        = errors.New("salary is already paid")
+	ErrExceedsPay         = errors.New("adjustments exceed the pay of the period")
+	// ErrPayrollConflict is returned when a salary record changed while it
+	// was being updated
+	ErrPayrollConflict = errors.New("salary record was updated by someone else")
+)
+
+// PeriodOf returns the payroll period t falls in
+func PeriodOf(t time.Time) string {
+	return t.Format(periodLayout)
+}
+
+// PeriodBounds returns the first instant of period and of the period after
+func PeriodBounds(period string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(periodLayout, period, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// Calculate fills in the gross salary, tax, pension and net pay of record
+// from its monthly salary, period and adjustments. A placement that starts or
+// ends within the period is paid for the calendar days it covers; a zero end
+// means it has not ended. Pension is only withheld when the placement is
+// enrolled in it.
+func Calculate(record *models.SalaryRecord, placementStart, placementEnd time.Time) {
+	from, to := record.PeriodStart, record.PeriodEnd
+	if placementStart.After(from) {
+		from = placementStart
+	}
+	if !placementEnd.IsZero() && placementEnd.Before(to) {
+		to = placementEnd
+	}
+
+	record.GrossSalary = record.MonthlySalary
+	if !from.Equal(record.PeriodStart) || !to.Equal(record.PeriodEnd) {
+		total := record.PeriodEnd.Sub(record.PeriodStart).Hours() / 24
+		worked := math.Max(to.Sub(from).Hours()/24, 0)
+		record.GrossSalary = round(record.MonthlySalary * worked / total)
+	}
+
+	record.IncomeTax = IncomeTax(record.GrossSalary)
+	record.EmployeePension = 0
+	record.EmployerPension = 0
+	if record.PensionEnrolled {
+		record.EmployeePension = EmployeePension(record.GrossSalary)
+		record.EmployerPension = EmployerPension(record.GrossSalary)
+	}
+
+	net := record.GrossSalary - record.IncomeTax - record.EmployeePension
+	for _, adjustment := range record.Adjustments {
+		net -= adjustment.Amount
+	}
+	record.NetPay = round(net)
+}
+
+// recalculate calculates record again after its adjustments changed, failing
+// with ErrExceedsPay when they take more than the period pays
+func recalculate(record *models.SalaryRecord, placementStart, placementEnd time.Time) error {
+	Calculate(record, placementStart, placementEnd)
+	if record.NetPay < 0 {
+		return ErrExceedsPay
+	}
+	return nil
+}
+
+// placementEnd returns when the placement of hiring ended, or is planned to,
+// and zero while it is open-ended. Hirings that ended before the end was
+// recorded fall back to their last update.
+func placementEnd(hiring models.Hiring) time.Time {
+	switch {
+	case !hiring.EndedAt.IsZero():
+		return hiring.EndedAt
+	case hiring.Status == models.Completed || hiring.Status == models.Terminated:
+		return hiring.UpdatedAt
+	default:
+		return hiring.EndDate
+	}
+}
+
+// Open creates the salary record of hiring for period
+func Open(ctx context.Context, hiring models.Hiring, period string) (models.SalaryRecord, error) {
+	if hiring.BookingMode == models.HourlyBooking || hiring.SalaryOffer <= 0 {
+		return models.SalaryRecord{}, ErrNotPlaced
+	}
+	switch hiring.Status {
+	case models.Approved, models.Completed, models.Terminated:
+	default:
+		return models.SalaryRecord{}, ErrNotPlaced
+	}
+
+	start, end, err := PeriodBounds(period)
+	if err != nil {
+		return models.SalaryRecord{}, err
+	}
+	now := time.Now()
+	ended := placementEnd(hiring)
+	if !end.After(hiring.StartDate) || start.After(now) || !ended.IsZero() && !start.Before(ended) {
+		return models.SalaryRecord{}, ErrPeriodOutOfRange
+	}
+
+	record := models.SalaryRecord{
+		HiringID:        hiring.ID,
+		EmployerID:      hiring.EmployerID,
+		HousekeeperID:   hiring.HousekeeperID,
+		Period:          period,
+		PeriodStart:     start,
+		PeriodEnd:       end,
+		MonthlySalary:   hiring.SalaryOffer,
+		PensionEnrolled: hiring.PensionEnrolled,
+		Adjustments:     []models.SalaryAdjustment{},
+		Status:          models.SalaryUnpaid,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	Calculate(&record, hiring.StartDate, ended)
+
+	err = events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		result, err := config.DB.Collection("salary_records").InsertOne(uow.Context(), record)
+		if err != nil {
+			return err
+		}
+		record.ID = result.InsertedID.(primitive.ObjectID)
+
+		return uow.Publish(events.SalaryRecordChanged{Record: record})
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return models.SalaryRecord{}, ErrPeriodOpen
+	}
+	return record, err
+}
+
+// OpenCurrentPeriods opens the payroll period of the current month for every
+// placement that has started. A placement that fails is logged and skipped,
+// and the failures are returned together once the others are opened.
+func OpenCurrentPeriods(ctx context.Context) error {
+	now := time.Now()
+	cursor, err := config.DB.Collection("hirings").Find(ctx, bson.M{
+		"status":       models.Approved,
+		"booking_mode": bson.M{"$ne": models.HourlyBooking},
+		"start_date":   bson.M{"$lte": now},
+	})
+	if err != nil {
+		return err
+	}
+
+	var hirings []models.Hiring
+	if err := cursor.All(ctx, &hirings); err != nil {
+		return err
+	}
+
+	period := PeriodOf(now)
+	var errs []error
+	for _, hiring := range hirings {
+		_, err := Open(ctx, hiring, period)
+		if err != nil && err != ErrPeriodOpen && err != ErrNotPlaced {
+			log.Printf("Error opening payroll period %s for hiring %s: %v", period, hiring.ID.Hex(), err)
+			errs = append(errs, fmt.Errorf("hiring %s: %w", hiring.ID.Hex(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Find returns the salary record of a hiring for period
+func Find(ctx context.Context, hiringID primitive.ObjectID, period string) (models.SalaryRecord, error) {
+	var record models.SalaryRecord
+	err := config.DB.Collection("salary_records").FindOne(ctx, bson.M{"hiring_id": hiringID, "period": period}).Decode(&record)
+	if err == mongo.ErrNoDocuments {
+		return record, ErrPeriodNotFound
+	}
+	return record, err
+}
+
+// AddAdjustment records a deduction or advance against an unpaid period
+func AddAdjustment(ctx context.Context, hiring models.Hiring, period string, adjustment models.SalaryAdjustment) (models.SalaryRecord, error) {
+	return updateUnpaid(ctx, hiring, period, func(record *models.SalaryRecord) error {
+		record.Adjustments = append(record.Adjustments, adjustment)
+		return nil
+	})
+}
+
+// RemoveAdjustment deletes a deduction or advance from an unpaid period
+func RemoveAdjustment(ctx context.Context, hiring models.Hiring, period string, adjustmentID primitive.ObjectID) (models.SalaryRecord, error) {
+	return updateUnpaid(ctx, hiring, period, func(record *models.SalaryRecord) error {
+		for i, adjustment := range record.Adjustments {
+			if adjustment.ID == adjustmentID {
+				record.Adjustments = append(record.Adjustments[:i], record.Adjustments[i+1:]...)
+				return nil
+			}
+		}
+		return ErrAdjustmentNotFound
+	})
+}
+
+// updateUnpaid applies change to the adjustments of an unpaid period and
+// recalculates its pay
+func updateUnpaid(ctx context.Context, hiring models.Hiring, period string, change func(*models.SalaryRecord) error) (models.SalaryRecord, error) {
+	record, err := Find(ctx, hiring.ID, period)
+	if err != nil {
+		return record, err
+	}
+	if record.Status != models.SalaryUnpaid {
+		return record, ErrAlreadyPaid
+	}
+
+	if err := change(&record); err != nil {
+		return record, err
+	}
+	if err := recalculate(&record, hiring.StartDate, placementEnd(hiring)); err != nil {
+		return record, err
+	}
+
+	previousUpdate := record.UpdatedAt
+	err = events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		record.UpdatedAt = time.Now()
+		// Matching on the last update rejects a concurrent change instead of
+		// overwriting its adjustments
+		result, err := config.DB.Collection("salary_records").UpdateOne(uow.Context(),
+			bson.M{"_id": record.ID, "status": models.SalaryUnpaid, "updated_at": previousUpdate},
+			bson.M{"$set": bson.M{
+				"adjustments":      record.Adjustments,
+				"gross_salary":     record.GrossSalary,
+				"income_tax":       record.IncomeTax,
+				"employee_pension": record.EmployeePension,
+				"employer_pension": record.EmployerPension,
+				"net_pay":          record.NetPay,
+				"updated_at":       record.UpdatedAt,
+			}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrPayrollConflict
+		}
+		return uow.Publish(events.SalaryRecordChanged{Record: record, PreviousStatus: models.SalaryUnpaid})
+	})
+	return record, err
+}
+
+// MarkPaid records that the salary of period was paid. paidBy is the party
+// that reported it.
+func MarkPaid(ctx context.Context, hiringID primitive.ObjectID, period, paidBy, note string) (models.SalaryRecord, error) {
+	var record models.SalaryRecord
+	err := events.RunInTransaction(ctx, func(uow *events.UnitOfWork) error {
+		now := time.Now()
+		err := config.DB.Collection("salary_records").FindOneAndUpdate(uow.Context(),
+			bson.M{"hiring_id": hiringID, "period": period, "status": models.SalaryUnpaid},
+			bson.M{"$set": bson.M{
+				"status":       models.SalaryPaid,
+				"paid_by":      paidBy,
+				"payment_note": note,
+				"paid_at":      now,
+				"updated_at":   now,
+			}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&record)
+		if err == mongo.ErrNoDocuments {
+			if _, err := Find(uow.Context(), hiringID, period); err != nil {
+				return err
+			}
+			return ErrAlreadyPaid
+		}
+		if err != nil {
+			return err
+		}
+		return uow.Publish(events.SalaryRecordChanged{Record: record, PreviousStatus: models.SalaryUnpaid})
+	})
+	return record, err
+}
@@ -0,0 +1,111 @@
+package payroll
+
+import (
+	"backend/models"
+	"testing"
+	"time"
+)
+
+func february(t *testing.T) models.SalaryRecord {
+	t.Helper()
+	start, end, err := PeriodBounds("2024-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.SalaryRecord{Period: "2024-02", PeriodStart: start, PeriodEnd: end, MonthlySalary: 6000}
+}
+
+func day(d int) time.Time {
+	return time.Date(2024, time.February, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestCalculate(t *testing.T) {
+	before := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.Local)
+	after := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name        string
+		start, end  time.Time
+		pension     bool
+		adjustments []models.SalaryAdjustment
+		gross, tax  float64
+		employee    float64
+		employer    float64
+		net         float64
+	}{
+		{name: "whole month", start: before, gross: 6000, tax: 700, net: 5300},
+		{name: "ends after the month", start: before, end: after, gross: 6000, tax: 700, net: 5300},
+		{name: "enrolled in pension", start: before, pension: true, gross: 6000, tax: 700, employee: 420, employer: 660, net: 4880},
+		// February 2024 has 29 days
+		{name: "starts in the month", start: day(15), gross: 3103.45, tax: 165.52, net: 2937.93},
+		{name: "ends in the month", start: before, end: day(10), gross: 1862.07, tax: 0, net: 1862.07},
+		{name: "starts and ends in the month", start: day(5), end: day(20), gross: 3103.45, tax: 165.52, net: 2937.93},
+		{name: "ended before the month", start: before, end: before.AddDate(0, 1, 0), gross: 0, tax: 0, net: 0},
+		{
+			name:        "with adjustments",
+			start:       before,
+			adjustments: []models.SalaryAdjustment{{Type: models.SalaryDeduction, Amount: 500}, {Type: models.SalaryAdvance, Amount: 300}},
+			gross:       6000,
+			tax:         700,
+			net:         4500,
+		},
+	}
+
+	for _, test := range tests {
+		record := february(t)
+		record.PensionEnrolled = test.pension
+		record.Adjustments = test.adjustments
+		Calculate(&record, test.start, test.end)
+
+		if record.GrossSalary != test.gross || record.IncomeTax != test.tax || record.NetPay != test.net ||
+			record.EmployeePension != test.employee || record.EmployerPension != test.employer {
+			t.Errorf("%s: got gross %g, tax %g, pension %g and %g, net %g; want %g, %g, %g and %g, %g", test.name,
+				record.GrossSalary, record.IncomeTax, record.EmployeePension, record.EmployerPension, record.NetPay,
+				test.gross, test.tax, test.employee, test.employer, test.net)
+		}
+	}
+}
+
+func TestRecalculate(t *testing.T) {
+	before := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		deductions float64
+		want       error
+	}{
+		{0, nil},
+		{5300, nil},
+		{5300.01, ErrExceedsPay},
+		{10000, ErrExceedsPay},
+	}
+
+	for _, test := range tests {
+		record := february(t)
+		record.Adjustments = []models.SalaryAdjustment{{Type: models.SalaryDeduction, Amount: test.deductions}}
+		if err := recalculate(&record, before, time.Time{}); err != test.want {
+			t.Errorf("recalculate with %g deducted = %v, want %v", test.deductions, err, test.want)
+		}
+	}
+}
+
+func TestPlacementEnd(t *testing.T) {
+	ended := day(10)
+	updated := day(12)
+	planned := day(20)
+
+	tests := []struct {
+		hiring models.Hiring
+		want   time.Time
+	}{
+		{models.Hiring{Status: models.Approved}, time.Time{}},
+		{models.Hiring{Status: models.Approved, EndDate: planned}, planned},
+		{models.Hiring{Status: models.Completed, EndDate: planned, EndedAt: ended, UpdatedAt: updated}, ended},
+		{models.Hiring{Status: models.Terminated, UpdatedAt: updated}, updated},
+	}
+
+	for _, test := range tests {
+		if got := placementEnd(test.hiring); !got.Equal(test.want) {
+			t.Errorf("placementEnd of a %s hiring = %s, want %s", test.hiring.Status, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package payroll
+
+import (
+	"backend/contracts"
+	"backend/models"
+	"backend/pdf"
+	"fmt"
+)
+
+var adjustmentLabels = map[models.SalaryAdjustmentType]string{
+	models.SalaryDeduction: "Deduction",
+	models.SalaryAdvance:   "Advance",
+}
+
+// Payslip renders the salary record of a period as a PDF for the housekeeper
+func Payslip(record models.SalaryRecord, employer models.Employer, housekeeper models.Housekeeper) []byte {
+	month := record.PeriodStart.Format("January 2006")
+	doc := pdf.New("Payslip " + month)
+	doc.Title("Payslip")
+	doc.Note(fmt.Sprintf("%s - %s to %s", month,
+		record.PeriodStart.Format("2 January 2006"), record.PeriodEnd.AddDate(0, 0, -1).Format("2 January 2006")))
+	doc.Rule()
+
+	offsets := []float64{0, 140}
+	doc.Columns([]string{"Employee", housekeeper.Name}, offsets, false)
+	doc.Columns([]string{"Employer", employer.Name}, offsets, false)
+	doc.Columns([]string{"Employer address", employer.Address}, offsets, false)
+
+	doc.Heading("Earnings")
+	amounts := []float64{0, 380}
+	doc.Columns([]string{"Monthly salary", contracts.Money(record.MonthlySalary)}, amounts, false)
+	gross := "Gross pay"
+	if record.GrossSalary != record.MonthlySalary {
+		gross += ", prorated for the days worked"
+	}
+	doc.Columns([]string{gross, contracts.Money(record.GrossSalary)}, amounts, true)
+
+	doc.Heading("Deductions")
+	doc.Columns([]string{"Income tax", contracts.Money(record.IncomeTax)}, amounts, false)
+	if record.PensionEnrolled {
+		doc.Columns([]string{fmt.Sprintf("Pension, employee %g%%", EmployeePensionRate*100), contracts.Money(record.EmployeePension)}, amounts, false)
+	}
+	total := record.IncomeTax + record.EmployeePension
+	for _, adjustment := range record.Adjustments {
+		// Columns do not wrap, so long descriptions are shortened to stay
+		// clear of the amounts
+		label := adjustmentLabels[adjustment.Type] + ": " + adjustment.Description
+		if runes := []rune(label); len(runes) > 70 {
+			label = string(runes[:67]) + "..."
+		}
+		doc.Columns([]string{label, contracts.Money(adjustment.Amount)}, amounts, false)
+		total += adjustment.Amount
+	}
+	doc.Columns([]string{"Total deductions", contracts.Money(total)}, amounts, true)
+
+	doc.Rule()
+	doc.Columns([]string{"Net pay", contracts.Money(record.NetPay)}, amounts, true)
+	doc.Space(6)
+
+	if record.Status == models.SalaryPaid {
+		doc.Paragraph(fmt.Sprintf("Paid on %s.", record.PaidAt.Format("2 January 2006")))
+	} else {
+		doc.Paragraph("Not paid yet.")
+	}
+	if record.PensionEnrolled {
+		doc.Note(fmt.Sprintf("The employer also contributes %s (%g%%) to the pension fund.",
+			contracts.Money(record.EmployerPension), EmployerPensionRate*100))
+	}
+	doc.Note("Hiring " + record.HiringID.Hex())
+
+	return doc.Bytes()
+}
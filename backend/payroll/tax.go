@@ -0,0 +1,62 @@
+package payroll
+
+import "math"
+
+// Pension contribution rates of the private organisation pension scheme under
+// the Private Organization Employees' Pension Proclamation No. 715/2011. The
+// proclamation does not clearly cover domestic workers, so contributions are
+// only made for placements enrolled in the scheme.
+const (
+	EmployeePensionRate = 0.07
+	EmployerPensionRate = 0.11
+)
+
+// taxBracket applies Rate to a monthly salary up to UpTo birr, less
+// Deduction, which is the shortcut for taxing only the part above the lower
+// brackets at Rate
+type taxBracket struct {
+	UpTo      float64
+	Rate      float64
+	Deduction float64
+}
+
+// Monthly employment income tax brackets of the Federal Income Tax
+// Proclamation No. 979/2016, as amended with the brackets in force from 2025
+var taxBrackets = []taxBracket{
+	{UpTo: 2000, Rate: 0, Deduction: 0},
+	{UpTo: 4000, Rate: 0.15, Deduction: 300},
+	{UpTo: 7000, Rate: 0.20, Deduction: 500},
+	{UpTo: 10000, Rate: 0.25, Deduction: 850},
+	{UpTo: 14000, Rate: 0.30, Deduction: 1350},
+	{UpTo: math.Inf(1), Rate: 0.35, Deduction: 2050},
+}
+
+// IncomeTax returns the employment income tax on a monthly gross salary
+func IncomeTax(gross float64) float64 {
+	if gross <= 0 {
+		return 0
+	}
+	for _, bracket := range taxBrackets {
+		if gross <= bracket.UpTo {
+			return round(gross*bracket.Rate - bracket.Deduction)
+		}
+	}
+	return 0
+}
+
+// EmployeePension returns the pension contribution withheld from a gross
+// salary
+func EmployeePension(gross float64) float64 {
+	return round(gross * EmployeePensionRate)
+}
+
+// EmployerPension returns the pension contribution the employer pays on top
+// of a gross salary
+func EmployerPension(gross float64) float64 {
+	return round(gross * EmployerPensionRate)
+}
+
+// round rounds an amount to whole santim
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,39 @@
+package payroll
+
+import "testing"
+
+func TestIncomeTax(t *testing.T) {
+	tests := []struct {
+		gross float64
+		want  float64
+	}{
+		{-100, 0},
+		{0, 0},
+		{2000, 0},
+		{2001, 0.15},
+		{4000, 300},
+		{4001, 300.2},
+		{7000, 900},
+		{7001, 900.25},
+		{10000, 1650},
+		{10001, 1650.3},
+		{14000, 2850},
+		{14001, 2850.35},
+		{20000, 4950},
+	}
+
+	for _, test := range tests {
+		if got := IncomeTax(test.gross); got != test.want {
+			t.Errorf("IncomeTax(%g) = %g, want %g", test.gross, got, test.want)
+		}
+	}
+}
+
+func TestPension(t *testing.T) {
+	if got := EmployeePension(6000); got != 420 {
+		t.Errorf("EmployeePension(6000) = %g, want 420", got)
+	}
+	if got := EmployerPension(6000); got != 660 {
+		t.Errorf("EmployerPension(6000) = %g, want 660", got)
+	}
+}
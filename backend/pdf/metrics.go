@@ -0,0 +1,60 @@
+package pdf
+
+import "strings"
+
+// helveticaWidths are the Helvetica glyph widths of the printable ASCII
+// characters, in thousandths of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+	278, 278, 584, 584, 584, 556, 1015, // : to @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A to M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+	278, 278, 278, 469, 556, 333, // [ to `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a to m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n to z
+	334, 260, 334, 584, // { to ~
+}
+
+// textWidth estimates the width of s in points. Bold text is about six
+// percent wider than regular text, and characters outside ASCII are counted
+// as average width unless unicode is printing them; both are close enough
+// for wrapping.
+func textWidth(f font, unicode *trueType, s string) float64 {
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r < 127 {
+			total += helveticaWidths[r-32]
+		} else if glyph, ok := unicode.glyph(r); ok {
+			total += unicode.width(glyph)
+		} else {
+			total += 556
+		}
+	}
+	width := float64(total) * f.size / 1000
+	if f.bold {
+		width *= 1.06
+	}
+	return width
+}
+
+// wrap splits text into lines no wider than width, breaking at spaces.
+// Words wider than a line are put on a line of their own.
+func wrap(f font, unicode *trueType, text string, width float64) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := words[0]
+	for _, word := range words[1:] {
+		if textWidth(f, unicode, line+" "+word) > width {
+			lines = append(lines, line)
+			line = word
+		} else {
+			line += " " + word
+		}
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,337 @@
+// Package pdf writes simple text documents as PDF files. Text is set in the
+// standard Helvetica fonts, which every PDF reader ships. Characters outside
+// the Windows-1252 character set, such as Ge'ez script, are set in the
+// TrueType font configured with PDF_UNICODE_FONT, which is embedded in the
+// document, and printed as question marks when there is none.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 page size and margins, in points
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 56.0
+)
+
+type font struct {
+	name string
+	size float64
+	bold bool
+}
+
+var (
+	titleFont   = font{name: "F2", size: 16, bold: true}
+	headingFont = font{name: "F2", size: 12, bold: true}
+	bodyFont    = font{name: "F1", size: 10.5}
+	smallFont   = font{name: "F1", size: 8.5}
+)
+
+// Document is a PDF document being written. Text flows from the top of the
+// first page and continues on new pages as needed.
+type Document struct {
+	title string
+	pages []*bytes.Buffer
+	y     float64
+	// unicode is the embedded font, and used the glyphs printed with it
+	unicode *trueType
+	used    map[uint16]rune
+}
+
+// New starts a document with one empty page. The title is stored in the
+// document information.
+func New(title string) *Document {
+	d := &Document{title: title, unicode: loadUnicodeFont(), used: map[uint16]rune{}}
+	d.newPage()
+	return d
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Title writes a large bold line
+func (d *Document) Title(text string) {
+	d.writeLines(titleFont, text, 0)
+	d.Space(6)
+}
+
+// Heading writes a bold line, with some space above it
+func (d *Document) Heading(text string) {
+	d.Space(8)
+	d.writeLines(headingFont, text, 0)
+	d.Space(2)
+}
+
+// Paragraph writes text wrapped to the page width. Line breaks in text are
+// kept.
+func (d *Document) Paragraph(text string) {
+	d.writeLines(bodyFont, text, 0)
+	d.Space(4)
+}
+
+// Note writes text in a small font
+func (d *Document) Note(text string) {
+	d.writeLines(smallFont, text, 0)
+}
+
+// Columns writes one line of cells starting at the given x offsets from the
+// left margin. Cells wider than their column are not wrapped.
+func (d *Document) Columns(cells []string, offsets []float64, bold bool) {
+	f := bodyFont
+	if bold {
+		f = font{name: "F2", size: bodyFont.size, bold: true}
+	}
+	d.ensureRoom(f.size * 1.4)
+	for i, cell := range cells {
+		if i < len(offsets) {
+			d.text(f, margin+offsets[i], cell)
+		}
+	}
+	d.y -= f.size * 1.4
+}
+
+// Rule draws a horizontal line across the page
+func (d *Document) Rule() {
+	d.ensureRoom(8)
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, d.y, pageWidth-margin, d.y)
+	d.y -= 8
+}
+
+// Space moves the cursor down by points
+func (d *Document) Space(points float64) {
+	d.y -= points
+	if d.y < margin {
+		d.newPage()
+	}
+}
+
+func (d *Document) writeLines(f font, text string, indent float64) {
+	width := pageWidth - 2*margin - indent
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range wrap(f, d.unicode, paragraph, width) {
+			d.ensureRoom(f.size * 1.4)
+			d.text(f, margin+indent, line)
+			d.y -= f.size * 1.4
+		}
+	}
+}
+
+func (d *Document) ensureRoom(height float64) {
+	if d.y-height < margin {
+		d.newPage()
+	}
+}
+
+// text writes s at x on the current line. Runs of characters only the
+// embedded font has are switched to it, drawn with an outline for bold text
+// since there is no bold variant; each run starts where the previous one
+// ended.
+func (d *Document) text(f font, x float64, s string) {
+	page := d.page()
+	fmt.Fprintf(page, "BT %.2f %.2f Td", x, d.y-f.size)
+
+	var run strings.Builder
+	inUnicode := false
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		if inUnicode {
+			if f.bold {
+				fmt.Fprintf(page, " 2 Tr %.2f w", f.size*0.03)
+			}
+			fmt.Fprintf(page, " /F3 %.1f Tf <%s> Tj", f.size, run.String())
+			if f.bold {
+				page.WriteString(" 0 Tr")
+			}
+		} else {
+			fmt.Fprintf(page, " /%s %.1f Tf (%s) Tj", f.name, f.size, escape(run.String()))
+		}
+		run.Reset()
+	}
+
+	for _, r := range s {
+		glyph, ok := d.unicode.glyph(r)
+		// Spaces between words of the embedded font stay in its run
+		if !ok && r == ' ' && inUnicode {
+			glyph, ok = d.unicode.glyphs[r]
+		}
+		if ok != inUnicode {
+			flush()
+			inUnicode = ok
+		}
+		if ok {
+			d.used[glyph] = r
+			fmt.Fprintf(&run, "%04X", glyph)
+		} else {
+			run.WriteRune(r)
+		}
+	}
+	flush()
+	page.WriteString(" ET\n")
+}
+
+// Bytes renders the document
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1 to 4 are fixed; each page then takes a page and a content
+	// object
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	// The embedded font follows the pages
+	fonts := "/F1 3 0 R /F2 4 0 R"
+	if len(d.used) > 0 {
+		fonts += fmt.Sprintf(" /F3 %d 0 R", 5+2*len(d.pages))
+	}
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fonts, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+	if len(d.used) > 0 {
+		d.embedFont(object, len(offsets)+1)
+	}
+	object(fmt.Sprintf("<< /Title %s /Producer (AGAZH) >>", textString(d.title)))
+	info := len(offsets)
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, info, xref)
+	return out.Bytes()
+}
+
+// embedFont writes the embedded font as a Type 0 font with Identity-H
+// encoding, so text selects glyphs by ID, and a ToUnicode map so that the
+// text can be copied and searched. first is the number of its first object;
+// the font is written whole, as it is only embedded when it is needed.
+func (d *Document) embedFont(object func(string), first int) {
+	font := d.unicode
+	glyphs := make([]int, 0, len(d.used))
+	for glyph := range d.used {
+		glyphs = append(glyphs, int(glyph))
+	}
+	sort.Ints(glyphs)
+
+	var widths, cmap strings.Builder
+	for _, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", glyph, font.width(uint16(glyph)))
+	}
+	fmt.Fprintf(&cmap, "/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n"+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n"+
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n"+
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// bfchar sections hold at most 100 entries
+	for start := 0; start < len(glyphs); start += 100 {
+		end := start + 100
+		if end > len(glyphs) {
+			end = len(glyphs)
+		}
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for _, glyph := range glyphs[start:end] {
+			fmt.Fprintf(&cmap, "<%04X> <", glyph)
+			for _, unit := range utf16.Encode([]rune{d.used[uint16(glyph)]}) {
+				fmt.Fprintf(&cmap, "%04X", unit)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+
+	var file bytes.Buffer
+	writer := zlib.NewWriter(&file)
+	writer.Write(font.data)
+	writer.Close()
+
+	object(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		font.name, first+1, first+2))
+	object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>", font.name, first+3, widths.String()))
+	object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", cmap.Len(), cmap.String()))
+	object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		font.name, font.scale(font.bbox[0]), font.scale(font.bbox[1]), font.scale(font.bbox[2]), font.scale(font.bbox[3]),
+		font.scale(font.ascent), font.scale(font.descent), font.scale(font.ascent), first+4))
+	object(fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream", file.Len(), len(font.data), file.String()))
+}
+
+// textString converts s to a PDF text string for the document information,
+// in UTF-16 when Windows-1252 cannot hold it
+func textString(s string) string {
+	for _, r := range s {
+		if !encodable(r) {
+			var b strings.Builder
+			b.WriteString("<FEFF")
+			for _, unit := range utf16.Encode([]rune(s)) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">")
+			return b.String()
+		}
+	}
+	return "(" + escape(s) + ")"
+}
+
+// encodable reports whether r is in Windows-1252, and so in Helvetica
+func encodable(r rune) bool {
+	_, ok := winAnsi[r]
+	return r >= 32 && r < 127 || r >= 0xa0 && r <= 0xff || ok
+}
+
+// escape converts s to a Windows-1252 PDF string literal body
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			if c, ok := winAnsi[r]; ok {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
+
+// winAnsi maps the printable characters of Windows-1252 outside Latin-1
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, '‰': 0x89,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
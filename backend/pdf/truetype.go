@@ -0,0 +1,269 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// trueType is a TrueType font embedded whole in documents that need
+// characters Helvetica does not have, such as Ge'ez script
+type trueType struct {
+	data       []byte
+	name       string
+	unitsPerEm int
+	ascent     int
+	descent    int
+	bbox       [4]int
+	glyphs     map[rune]uint16
+	advances   []uint16
+}
+
+var (
+	unicodeFontOnce sync.Once
+	unicodeFont     *trueType
+)
+
+// loadUnicodeFont reads the font configured with PDF_UNICODE_FONT, the path
+// of a TrueType file such as Noto Sans Ethiopic. Without it, or when it
+// cannot be read, documents only use Helvetica.
+func loadUnicodeFont() *trueType {
+	unicodeFontOnce.Do(func() {
+		path := os.Getenv("PDF_UNICODE_FONT")
+		if path == "" {
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Error reading PDF font %s: %v", path, err)
+			return
+		}
+		font, err := parseTrueType(data)
+		if err != nil {
+			log.Printf("Error reading PDF font %s: %v", path, err)
+			return
+		}
+		unicodeFont = font
+	})
+	return unicodeFont
+}
+
+var errInvalidFont = errors.New("not a TrueType font")
+
+// parseTrueType reads the tables a PDF needs to place text in the font:
+// head, hhea, maxp, hmtx and cmap, and the PostScript name when there is one
+func parseTrueType(data []byte) (*trueType, error) {
+	if len(data) < 12 || binary.BigEndian.Uint32(data) != 0x00010000 && string(data[:4]) != "true" {
+		return nil, errInvalidFont
+	}
+
+	tables := map[string][]byte{}
+	count := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < count; i++ {
+		entry := 12 + 16*i
+		if entry+16 > len(data) {
+			return nil, errInvalidFont
+		}
+		offset := int(binary.BigEndian.Uint32(data[entry+8:]))
+		length := int(binary.BigEndian.Uint32(data[entry+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, errInvalidFont
+		}
+		tables[string(data[entry:entry+4])] = data[offset : offset+length]
+	}
+
+	head, hhea, maxp, hmtx := tables["head"], tables["hhea"], tables["maxp"], tables["hmtx"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 || tables["cmap"] == nil || tables["glyf"] == nil {
+		return nil, errInvalidFont
+	}
+
+	font := &trueType{
+		data:       data,
+		name:       "EmbeddedFont",
+		unitsPerEm: int(binary.BigEndian.Uint16(head[18:])),
+		ascent:     int(int16(binary.BigEndian.Uint16(hhea[4:]))),
+		descent:    int(int16(binary.BigEndian.Uint16(hhea[6:]))),
+	}
+	if font.unitsPerEm == 0 {
+		return nil, errInvalidFont
+	}
+	for i := range font.bbox {
+		font.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	metrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if metrics == 0 || metrics > numGlyphs || len(hmtx) < 4*metrics {
+		return nil, errInvalidFont
+	}
+	font.advances = make([]uint16, numGlyphs)
+	for i := range font.advances {
+		if i < metrics {
+			font.advances[i] = binary.BigEndian.Uint16(hmtx[4*i:])
+		} else {
+			font.advances[i] = font.advances[metrics-1]
+		}
+	}
+
+	glyphs, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	font.glyphs = glyphs
+
+	if name := postScriptName(tables["name"]); name != "" {
+		font.name = name
+	}
+	return font, nil
+}
+
+// parseCmap reads the Unicode character to glyph mapping, from a format 12
+// subtable when the font has one and a format 4 one otherwise
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, errInvalidFont
+	}
+
+	var format4, format12 []byte
+	count := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < count; i++ {
+		record := 4 + 8*i
+		if record+8 > len(cmap) {
+			return nil, errInvalidFont
+		}
+		platform := binary.BigEndian.Uint16(cmap[record:])
+		encoding := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset+4 > len(cmap) || platform != 0 && !(platform == 3 && (encoding == 1 || encoding == 10)) {
+			continue
+		}
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			format4 = cmap[offset:]
+		case 12:
+			format12 = cmap[offset:]
+		}
+	}
+
+	glyphs := map[rune]uint16{}
+	switch {
+	case len(format12) >= 16:
+		groups := int(binary.BigEndian.Uint32(format12[12:]))
+		if 16+12*groups > len(format12) {
+			return nil, errInvalidFont
+		}
+		for i := 0; i < groups; i++ {
+			group := format12[16+12*i:]
+			start := binary.BigEndian.Uint32(group)
+			end := binary.BigEndian.Uint32(group[4:])
+			glyph := binary.BigEndian.Uint32(group[8:])
+			for c := start; c <= end && c <= 0x10ffff; c++ {
+				glyphs[rune(c)] = uint16(glyph + c - start)
+			}
+		}
+	case len(format4) >= 14:
+		segments := int(binary.BigEndian.Uint16(format4[6:])) / 2
+		ends, starts := 14, 16+2*segments
+		deltas, rangeOffsets := starts+2*segments, starts+4*segments
+		if rangeOffsets+2*segments > len(format4) {
+			return nil, errInvalidFont
+		}
+		for i := 0; i < segments; i++ {
+			start := int(binary.BigEndian.Uint16(format4[starts+2*i:]))
+			end := int(binary.BigEndian.Uint16(format4[ends+2*i:]))
+			delta := binary.BigEndian.Uint16(format4[deltas+2*i:])
+			rangeOffset := int(binary.BigEndian.Uint16(format4[rangeOffsets+2*i:]))
+			for c := start; c <= end && c != 0xffff; c++ {
+				glyph := uint16(c) + delta
+				if rangeOffset != 0 {
+					at := rangeOffsets + 2*i + rangeOffset + 2*(c-start)
+					if at+2 > len(format4) {
+						return nil, errInvalidFont
+					}
+					glyph = binary.BigEndian.Uint16(format4[at:])
+					if glyph != 0 {
+						glyph += delta
+					}
+				}
+				if glyph != 0 {
+					glyphs[rune(c)] = glyph
+				}
+			}
+		}
+	default:
+		return nil, errors.New("font has no Unicode character map")
+	}
+	return glyphs, nil
+}
+
+// postScriptName returns name 6 of the name table, keeping only the
+// characters a PDF name allows without escapes
+func postScriptName(table []byte) string {
+	if len(table) < 6 {
+		return ""
+	}
+	count := int(binary.BigEndian.Uint16(table[2:]))
+	storage := int(binary.BigEndian.Uint16(table[4:]))
+	for i := 0; i < count; i++ {
+		record := 6 + 12*i
+		if record+12 > len(table) {
+			return ""
+		}
+		platform := binary.BigEndian.Uint16(table[record:])
+		if binary.BigEndian.Uint16(table[record+6:]) != 6 {
+			continue
+		}
+		length := int(binary.BigEndian.Uint16(table[record+8:]))
+		offset := storage + int(binary.BigEndian.Uint16(table[record+10:]))
+		if offset+length > len(table) {
+			return ""
+		}
+		raw := table[offset : offset+length]
+
+		var name strings.Builder
+		for j := 0; j < len(raw); j++ {
+			c := raw[j]
+			// Windows names are UTF-16BE; PostScript names are ASCII
+			if platform == 3 || platform == 0 {
+				if j+1 >= len(raw) || c != 0 {
+					break
+				}
+				j++
+				c = raw[j]
+			}
+			if c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c == '-' {
+				name.WriteByte(c)
+			}
+		}
+		if name.Len() > 0 {
+			return name.String()
+		}
+	}
+	return ""
+}
+
+// glyph returns the glyph printing r, for characters Helvetica does not
+// have. It is safe to call on a nil font.
+func (t *trueType) glyph(r rune) (uint16, bool) {
+	if t == nil || encodable(r) {
+		return 0, false
+	}
+	glyph, ok := t.glyphs[r]
+	return glyph, ok
+}
+
+// width returns the advance of glyph in thousandths of the font size
+func (t *trueType) width(glyph uint16) int {
+	if int(glyph) >= len(t.advances) {
+		return 0
+	}
+	return int(t.advances[glyph]) * 1000 / t.unitsPerEm
+}
+
+// scale converts font units to thousandths of the font size
+func (t *trueType) scale(units int) int {
+	return units * 1000 / t.unitsPerEm
+}
@@ -0,0 +1,144 @@
+package ratings
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"math"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// delta is the change a set of reviews makes to the stored aggregates
+type delta struct {
+	counts map[string]int
+	sums   map[string]float64
+}
+
+func newDelta() delta {
+	return delta{counts: map[string]int{}, sums: map[string]float64{}}
+}
+
+// add counts review towards the aggregates with the given sign. Hidden reviews
+// are not part of the aggregates, so they add nothing.
+func (d delta) add(review *models.Review, sign int) {
+	if review == nil || review.IsHidden() {
+		return
+	}
+
+	d.counts["rating_summary.count"] += sign
+	d.sums["rating_summary.sum"] += float64(sign) * review.Rating
+	// Half stars round up, as in Summarize
+	star := strconv.Itoa(int(math.Floor(review.Rating + 0.5)))
+	d.counts["rating_summary.distribution."+star] += sign
+
+	if review.Scores == nil {
+		return
+	}
+	for dimension, score := range review.Scores.ByDimension() {
+		d.counts["rating_summary.dimensions."+dimension+".count"] += sign
+		d.sums["rating_summary.dimensions."+dimension+".sum"] += float64(sign) * score
+	}
+}
+
+func (d delta) inc() bson.M {
+	inc := bson.M{}
+	for field, count := range d.counts {
+		if count != 0 {
+			inc[field] = count
+		}
+	}
+	for field, sum := range d.sums {
+		if sum != 0 {
+			inc[field] = sum
+		}
+	}
+	return inc
+}
+
+// Apply updates a housekeeper's aggregates incrementally, taking out the
+// contribution of removed and adding that of added. Either may be nil, so
+// creating, editing, deleting and moderating a review are all one call. The
+// counts and sums are incremented atomically; averages and the ranking score
+// are then derived from the stored totals, so concurrent reviews cannot lose
+// each other's updates.
+func Apply(ctx context.Context, housekeeperID primitive.ObjectID, removed, added *models.Review) error {
+	d := newDelta()
+	d.add(removed, -1)
+	d.add(added, 1)
+
+	inc := d.inc()
+	if len(inc) == 0 {
+		return nil
+	}
+
+	collection := config.DB.Collection("housekeepers")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var housekeeper models.Housekeeper
+	err := collection.FindOneAndUpdate(ctx, bson.M{"_id": housekeeperID}, bson.M{"$inc": inc}, opts).Decode(&housekeeper)
+	if err != nil {
+		return err
+	}
+
+	summary := housekeeper.RatingSummary
+	set := bson.M{
+		"rating":                 average(summary.Sum, summary.Count),
+		"rating_summary.average": average(summary.Sum, summary.Count),
+		"ranking_score":          Score(summary.Sum, summary.Count),
+	}
+	for dimension, aggregate := range summary.Dimensions {
+		set["rating_summary.dimensions."+dimension+".average"] = average(aggregate.Sum, aggregate.Count)
+	}
+
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": housekeeperID}, bson.M{"$set": set})
+	return err
+}
+
+// ApplyEmployer updates an employer's aggregates for a review being added,
+// removed or replaced. Pass nil for the side that does not apply. Hidden
+// reviews do not count.
+func ApplyEmployer(ctx context.Context, employerID primitive.ObjectID, removed, added *models.EmployerReview) error {
+	var count int
+	var sum float64
+	if removed != nil && !removed.IsHidden() {
+		count--
+		sum -= removed.Rating
+	}
+	if added != nil && !added.IsHidden() {
+		count++
+		sum += added.Rating
+	}
+	if count == 0 && sum == 0 {
+		return nil
+	}
+
+	collection := config.DB.Collection("employers")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var employer models.Employer
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": employerID},
+		bson.M{"$inc": bson.M{"rating_count": count, "rating_sum": sum}},
+		opts,
+	).Decode(&employer)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"_id": employerID},
+		bson.M{"$set": bson.M{"rating": average(employer.RatingSum, employer.RatingCount)}},
+	)
+	return err
+}
+
+func average(sum float64, count int) float64 {
+	if count <= 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
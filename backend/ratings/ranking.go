@@ -0,0 +1,30 @@
+package ratings
+
+import (
+	"os"
+	"strconv"
+)
+
+const (
+	defaultPriorMean   = 3.5
+	defaultPriorWeight = 5
+)
+
+// Score is the value housekeepers are ranked by: the Bayesian average of their
+// ratings. It behaves as if every housekeeper already had priorWeight reviews
+// of priorMean stars, so a single five-star review does not outrank fifty
+// reviews averaging 4.8, and a housekeeper without reviews ranks in the middle
+// rather than at the bottom. The prior can be tuned with RATING_PRIOR_MEAN and
+// RATING_PRIOR_WEIGHT.
+func Score(sum float64, count int) float64 {
+	mean := envFloat("RATING_PRIOR_MEAN", defaultPriorMean)
+	weight := envFloat("RATING_PRIOR_WEIGHT", defaultPriorWeight)
+	return (weight*mean + sum) / (weight + float64(count))
+}
+
+func envFloat(name string, fallback float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(name), 64); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
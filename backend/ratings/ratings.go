@@ -0,0 +1,220 @@
+package ratings
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmptySummary returns a summary with every dimension and star present, so
+// clients do not have to deal with missing keys
+func EmptySummary() models.RatingSummary {
+	summary := models.RatingSummary{
+		Dimensions:   map[string]models.DimensionRating{},
+		Distribution: map[string]int{},
+	}
+	for _, dimension := range models.RatingDimensions {
+		summary.Dimensions[dimension] = models.DimensionRating{}
+	}
+	for star := 1; star <= 5; star++ {
+		summary.Distribution[strconv.Itoa(star)] = 0
+	}
+	return summary
+}
+
+// Summarize aggregates the visible reviews of a housekeeper into a rating summary
+func Summarize(ctx context.Context, housekeeperID primitive.ObjectID) (models.RatingSummary, error) {
+	group := bson.M{
+		"_id":     nil,
+		"average": bson.M{"$avg": "$rating"},
+		"count":   bson.M{"$sum": 1},
+		"sum":     bson.M{"$sum": "$rating"},
+	}
+	for _, dimension := range models.RatingDimensions {
+		field := "$scores." + dimension
+		// $avg skips reviews that did not score the dimension
+		group[dimension+"_average"] = bson.M{"$avg": field}
+		group[dimension+"_count"] = bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{field, 0}}, 1, 0}}}
+		group[dimension+"_sum"] = bson.M{"$sum": field}
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"housekeeper_id": housekeeperID,
+			"status":         bson.M{"$nin": models.HiddenReviewStatuses},
+		}},
+		{"$facet": bson.M{
+			"overall": bson.A{bson.M{"$group": group}},
+			"distribution": bson.A{bson.M{"$group": bson.M{
+				// Half stars round up
+				"_id":   bson.M{"$floor": bson.M{"$add": bson.A{"$rating", 0.5}}},
+				"count": bson.M{"$sum": 1},
+			}}},
+		}},
+	}
+
+	cursor, err := config.DB.Collection("reviews").Aggregate(ctx, pipeline)
+	if err != nil {
+		return models.RatingSummary{}, err
+	}
+
+	var results []struct {
+		Overall      []bson.M `bson:"overall"`
+		Distribution []struct {
+			Star  float64 `bson:"_id"`
+			Count int     `bson:"count"`
+		} `bson:"distribution"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return models.RatingSummary{}, err
+	}
+
+	summary := EmptySummary()
+	if len(results) == 0 || len(results[0].Overall) == 0 {
+		return summary, nil
+	}
+
+	overall := results[0].Overall[0]
+	summary.Average = toFloat(overall["average"])
+	summary.Count = int(toFloat(overall["count"]))
+	summary.Sum = toFloat(overall["sum"])
+	for _, dimension := range models.RatingDimensions {
+		summary.Dimensions[dimension] = models.DimensionRating{
+			Average: toFloat(overall[dimension+"_average"]),
+			Count:   int(toFloat(overall[dimension+"_count"])),
+			Sum:     toFloat(overall[dimension+"_sum"]),
+		}
+	}
+	for _, bucket := range results[0].Distribution {
+		summary.Distribution[strconv.Itoa(int(bucket.Star))] += bucket.Count
+	}
+
+	return summary, nil
+}
+
+// Recompute rebuilds a housekeeper's rating aggregates and ranking score from
+// their reviews
+func Recompute(ctx context.Context, housekeeperID primitive.ObjectID) error {
+	summary, err := Summarize(ctx, housekeeperID)
+	if err != nil {
+		return err
+	}
+
+	_, err = config.DB.Collection("housekeepers").UpdateOne(ctx,
+		bson.M{"_id": housekeeperID},
+		bson.M{"$set": bson.M{
+			"rating":         summary.Average,
+			"rating_summary": summary,
+			"ranking_score":  Score(summary.Sum, summary.Count),
+		}},
+	)
+	return err
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
+// RecomputeEmployer rebuilds an employer's rating aggregates from their reviews
+func RecomputeEmployer(ctx context.Context, employerID primitive.ObjectID) error {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"employer_id": employerID,
+			"status":      bson.M{"$nin": models.HiddenReviewStatuses},
+		}},
+		{"$group": bson.M{
+			"_id":   nil,
+			"sum":   bson.M{"$sum": "$rating"},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+
+	cursor, err := config.DB.Collection("employer_reviews").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	var results []struct {
+		Sum   float64 `bson:"sum"`
+		Count int     `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return err
+	}
+
+	var sum float64
+	var count int
+	if len(results) > 0 {
+		sum, count = results[0].Sum, results[0].Count
+	}
+
+	_, err = config.DB.Collection("employers").UpdateOne(ctx,
+		bson.M{"_id": employerID},
+		bson.M{"$set": bson.M{"rating": average(sum, count), "rating_count": count, "rating_sum": sum}},
+	)
+	return err
+}
+
+// RebuildAll recomputes the rating aggregates of every housekeeper and
+// employer from the review collections. It repairs aggregates that drifted
+// from the reviews, for example after reviews were edited by hand.
+func RebuildAll(ctx context.Context) (housekeepers int, employers int, err error) {
+	housekeeperIDs, err := allIDs(ctx, "housekeepers")
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, id := range housekeeperIDs {
+		if err := Recompute(ctx, id); err != nil {
+			return housekeepers, employers, err
+		}
+		housekeepers++
+	}
+
+	employerIDs, err := allIDs(ctx, "employers")
+	if err != nil {
+		return housekeepers, employers, err
+	}
+	for _, id := range employerIDs {
+		if err := RecomputeEmployer(ctx, id); err != nil {
+			return housekeepers, employers, err
+		}
+		employers++
+	}
+
+	return housekeepers, employers, nil
+}
+
+func allIDs(ctx context.Context, collection string) ([]primitive.ObjectID, error) {
+	cursor, err := config.DB.Collection(collection).Find(ctx, bson.M{},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
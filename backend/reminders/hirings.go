@@ -0,0 +1,90 @@
+package reminders
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/notifications"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// hiringReminderLead is how long before an approved hiring starts both
+// parties are reminded of it, configured with HIRING_START_REMINDER_HOURS
+func hiringReminderLead() time.Duration {
+	if hours, err := strconv.Atoi(os.Getenv("HIRING_START_REMINDER_HOURS")); err == nil && hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// SendHiringStartReminders reminds both parties of the approved hirings
+// starting soon. Like SendInterviewReminders, each hiring is leased before
+// anything is sent, reminded_at is only set once every channel went out and a
+// failed reminder is retried after the lease.
+func SendHiringStartReminders(ctx context.Context) error {
+	collection := config.DB.Collection("hirings")
+
+	for {
+		now := time.Now()
+		var hiring models.Hiring
+		err := collection.FindOneAndUpdate(ctx,
+			bson.M{
+				"status":      models.Approved,
+				"start_date":  bson.M{"$gt": now, "$lte": now.Add(hiringReminderLead())},
+				"reminded_at": bson.M{"$exists": false},
+				"$or": []bson.M{
+					{"reminder_claimed_until": bson.M{"$exists": false}},
+					{"reminder_claimed_until": bson.M{"$lte": now}},
+				},
+			},
+			bson.M{"$set": bson.M{"reminder_claimed_until": now.Add(reminderLease)}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&hiring)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := remindHiringStart(ctx, collection, hiring); err != nil {
+			log.Printf("Error sending start reminder for hiring %s: %v", hiring.ID.Hex(), err)
+			continue
+		}
+		_, err = collection.UpdateOne(ctx,
+			bson.M{"_id": hiring.ID},
+			bson.M{
+				"$set":   bson.M{"reminded_at": time.Now()},
+				"$unset": bson.M{"reminder_claimed_until": ""},
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func remindHiringStart(ctx context.Context, collection *mongo.Collection, hiring models.Hiring) error {
+	body := fmt.Sprintf("Reminder: the hiring starts on %s", hiring.StartDate.Format("2006-01-02 15:04"))
+	data := map[string]interface{}{"hiring_id": hiring.ID}
+
+	return sendChannels(ctx, collection, hiring.ID, "reminder_channels", hiring.ReminderChannels, []channel{
+		{"employer_push", func(ctx context.Context) error {
+			return notifications.NotifyUser(ctx, hiring.EmployerID, "hiring.start_reminder", "Hiring starts soon", body, data)
+		}},
+		{"housekeeper_push", func(ctx context.Context) error {
+			return notifications.NotifyUser(ctx, hiring.HousekeeperID, "hiring.start_reminder", "Hiring starts soon", body, data)
+		}},
+		{"housekeeper_sms", func(ctx context.Context) error {
+			return notifications.SendSMS(ctx, hiring.HousekeeperID, "housekeeper", "hiring.start_reminder", "AGAZH: "+body)
+		}},
+	})
+}
@@ -0,0 +1,95 @@
+package reminders
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/notifications"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// interviewReminderLead is how long before a confirmed interview both parties
+// are reminded of it, configured with INTERVIEW_REMINDER_HOURS
+func interviewReminderLead() time.Duration {
+	if hours, err := strconv.Atoi(os.Getenv("INTERVIEW_REMINDER_HOURS")); err == nil && hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// SendInterviewReminders reminds both parties of the confirmed interviews
+// starting soon. Each interview is claimed for reminderLease before anything
+// is sent, so several instances do not remind it twice, and reminder_sent_at
+// is only set once every channel went out. A reminder that failed is retried
+// after the lease while the interview is still ahead.
+func SendInterviewReminders(ctx context.Context) error {
+	collection := config.DB.Collection("interviews")
+
+	for {
+		now := time.Now()
+		var interview models.Interview
+		err := collection.FindOneAndUpdate(ctx,
+			bson.M{
+				"status":           models.InterviewConfirmed,
+				"scheduled_at":     bson.M{"$gt": now, "$lte": now.Add(interviewReminderLead())},
+				"reminder_sent_at": bson.M{"$exists": false},
+				"$or": []bson.M{
+					{"reminder_claimed_until": bson.M{"$exists": false}},
+					{"reminder_claimed_until": bson.M{"$lte": now}},
+				},
+			},
+			bson.M{"$set": bson.M{"reminder_claimed_until": now.Add(reminderLease)}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&interview)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := remindInterview(ctx, collection, interview); err != nil {
+			log.Printf("Error sending reminder for interview %s: %v", interview.ID.Hex(), err)
+			continue
+		}
+		_, err = collection.UpdateOne(ctx,
+			bson.M{"_id": interview.ID},
+			bson.M{
+				"$set":   bson.M{"reminder_sent_at": time.Now()},
+				"$unset": bson.M{"reminder_claimed_until": ""},
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func remindInterview(ctx context.Context, collection *mongo.Collection, interview models.Interview) error {
+	when := interview.ScheduledAt.Format("2006-01-02 15:04")
+	body := fmt.Sprintf("Reminder: your interview is on %s", when)
+	if interview.Mode == models.InPersonInterview {
+		body = fmt.Sprintf("Reminder: your interview is on %s at %s", when, interview.Location)
+	}
+	data := map[string]interface{}{"hiring_id": interview.HiringID, "interview_id": interview.ID}
+
+	return sendChannels(ctx, collection, interview.ID, "reminder_channels", interview.ReminderChannels, []channel{
+		{"employer_push", func(ctx context.Context) error {
+			return notifications.NotifyUser(ctx, interview.EmployerID, "interview.reminder", "Upcoming interview", body, data)
+		}},
+		{"housekeeper_push", func(ctx context.Context) error {
+			return notifications.NotifyUser(ctx, interview.HousekeeperID, "interview.reminder", "Upcoming interview", body, data)
+		}},
+		{"housekeeper_sms", func(ctx context.Context) error {
+			return notifications.SendSMS(ctx, interview.HousekeeperID, "housekeeper", "interview.reminder", "AGAZH: "+body)
+		}},
+	})
+}
@@ -0,0 +1,54 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// reminderLease is how long an instance owns a claimed reminder. A reminder
+// that was not sent in full is retried once the lease runs out.
+const reminderLease = 10 * time.Minute
+
+// channel is one notification of a reminder, such as the push to the
+// employer or the SMS to the housekeeper
+type channel struct {
+	name string
+	send func(ctx context.Context) error
+}
+
+// sendChannels sends every channel of a reminder that is not in done yet and
+// records each one that succeeds in the doneField array of the document, so a
+// retry only sends the ones that failed. A failing channel does not stop the
+// others; their errors are joined.
+func sendChannels(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, doneField string, done []string, channels []channel) error {
+	var errs []error
+	for _, ch := range channels {
+		if contains(done, ch.name) {
+			continue
+		}
+		if err := ch.send(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.name, err))
+			continue
+		}
+		_, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$addToSet": bson.M{doneField: ch.name}})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
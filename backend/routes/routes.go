@@ -24,6 +24,10 @@ func SetupHousekeeperRoutes(router *gin.RouterGroup) {
 		housekeepers.GET("/:id", controllers.GetHousekeeper)
 		housekeepers.PUT("/:id", controllers.UpdateHousekeeper)
 		housekeepers.DELETE("/:id", controllers.DeleteHousekeeper)
+		housekeepers.GET("/:id/availability", controllers.GetAvailability)
+		housekeepers.PUT("/:id/availability", controllers.UpdateWeeklySchedule)
+		housekeepers.POST("/:id/availability/exceptions", controllers.AddAvailabilityException)
+		housekeepers.DELETE("/:id/availability/exceptions/:exception_id", controllers.DeleteAvailabilityException)
 	}
 }
 
@@ -33,6 +37,8 @@ func SetupEmployerRoutes(router *gin.RouterGroup) {
 	{
 		employers.GET("/:id", controllers.GetEmployer)
 		employers.PUT("/:id", controllers.UpdateEmployer)
+		employers.GET("/:id/invoices", controllers.GetEmployerInvoices)
+		employers.GET("/:id/invoices/:invoice_id/pdf", controllers.DownloadInvoice)
 	}
 }
 
@@ -41,8 +47,40 @@ func SetupHiringRoutes(router *gin.RouterGroup) {
 	hiring.Use(middleware.AuthMiddleware())
 	{
 		hiring.POST("", controllers.CreateHiring)
+		hiring.GET("/cancellation-reasons", controllers.GetCancellationReasons)
 		hiring.GET("/:id", controllers.GetHiringStatus)
 		hiring.PUT("/:id", controllers.UpdateHiringStatus)
+		hiring.POST("/:id/cancel", controllers.CancelHiring)
+		hiring.POST("/:id/replacement", controllers.RequestReplacement)
+		hiring.GET("/:id/delivery", controllers.GetHiringDelivery)
+		hiring.GET("/:id/contracts", controllers.GetContracts)
+		hiring.POST("/:id/contracts", controllers.GenerateContract)
+		hiring.POST("/:id/contracts/:version/sign", controllers.SignContract)
+		hiring.GET("/:id/contracts/:version/pdf", controllers.DownloadContract)
+		hiring.GET("/:id/payments", controllers.GetHiringPayments)
+		hiring.GET("/:id/payroll", controllers.GetPayroll)
+		hiring.POST("/:id/payroll", controllers.OpenPayrollPeriod)
+		hiring.POST("/:id/payroll/:period/adjustments", controllers.AddSalaryAdjustment)
+		hiring.DELETE("/:id/payroll/:period/adjustments/:adjustment_id", controllers.DeleteSalaryAdjustment)
+		hiring.POST("/:id/payroll/:period/pay", controllers.MarkSalaryPaid)
+		hiring.GET("/:id/payroll/:period/payslip", controllers.DownloadPayslip)
+		hiring.POST("/:id/offers", controllers.CreateOffer)
+		hiring.GET("/:id/offers", controllers.GetOffers)
+		hiring.POST("/:id/offers/:offer_id/accept", controllers.AcceptOffer)
+		hiring.POST("/:id/offers/:offer_id/decline", controllers.DeclineOffer)
+		hiring.POST("/:id/messages", controllers.SendMessage)
+		hiring.GET("/:id/messages", controllers.GetMessages)
+		hiring.POST("/:id/messages/read", controllers.MarkMessagesRead)
+		hiring.POST("/:id/interviews", controllers.ProposeInterview)
+		hiring.GET("/:id/interviews", controllers.GetInterviews)
+		hiring.POST("/:id/interviews/:interview_id/confirm", controllers.ConfirmInterview)
+		hiring.POST("/:id/interviews/:interview_id/reschedule", controllers.RescheduleInterview)
+		hiring.POST("/:id/interviews/:interview_id/cancel", controllers.CancelInterview)
+		hiring.POST("/:id/interviews/:interview_id/outcome", controllers.RecordInterviewOutcome)
+		hiring.GET("/:id/occurrences", controllers.GetOccurrences)
+		hiring.POST("/:id/occurrences/:occurrence_id/skip", controllers.SkipOccurrence)
+		hiring.POST("/:id/occurrences/:occurrence_id/cancel", controllers.CancelOccurrence)
+		hiring.POST("/:id/occurrences/:occurrence_id/complete", controllers.CompleteOccurrence)
 		hiring.GET("/employer/:employer_id", controllers.GetHiringHistory)
 	}
 }
@@ -53,5 +91,84 @@ func SetupRatingRoutes(router *gin.RouterGroup) {
 	{
 		ratings.POST("", controllers.CreateReview)
 		ratings.GET("/housekeeper/:id", controllers.GetHousekeeperReviews)
+		ratings.GET("/housekeeper/:id/summary", controllers.GetHousekeeperRatingSummary)
+		ratings.POST("/employer", controllers.CreateEmployerReview)
+		ratings.GET("/employer/:id", controllers.GetEmployerReviews)
+		ratings.POST("/employer/:id/report", controllers.ReportEmployerReview)
+		ratings.PUT("/:id", controllers.UpdateReview)
+		ratings.DELETE("/:id", controllers.DeleteReview)
+		ratings.POST("/:id/reply", controllers.ReplyToReview)
+		ratings.POST("/:id/report", controllers.ReportReview)
+	}
+}
+
+func SetupDeviceRoutes(router *gin.RouterGroup) {
+	devices := router.Group("/devices")
+	devices.Use(middleware.AuthMiddleware())
+	{
+		devices.POST("", controllers.RegisterDevice)
+		devices.GET("", controllers.GetDevices)
+		devices.DELETE("/:device_id", controllers.UnregisterDevice)
+	}
+}
+
+func SetupNotificationRoutes(router *gin.RouterGroup) {
+	notifications := router.Group("/notifications")
+	notifications.Use(middleware.AuthMiddleware())
+	{
+		notifications.PUT("/sms", controllers.UpdateSMSPreferences)
+	}
+}
+
+func SetupWebhookRoutes(router *gin.RouterGroup) {
+	webhooks := router.Group("/webhooks")
+	webhooks.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		webhooks.POST("", controllers.CreateWebhook)
+		webhooks.GET("", controllers.GetWebhooks)
+		webhooks.PUT("/:id", controllers.UpdateWebhook)
+		webhooks.DELETE("/:id", controllers.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", controllers.GetWebhookDeliveries)
+	}
+}
+
+func SetupPaymentRoutes(router *gin.RouterGroup) {
+	payments := router.Group("/payments")
+	{
+		payments.POST("/webhooks/:provider", controllers.HandlePaymentWebhook)
+	}
+
+	fake := payments.Group("/fake")
+	fake.Use(middleware.AuthMiddleware())
+	{
+		fake.POST("/:reference/pay", controllers.PayFakePayment)
+	}
+}
+
+func SetupAdminRoutes(router *gin.RouterGroup) {
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.GET("/moderation/reviews", controllers.GetModerationQueue)
+		admin.POST("/moderation/reviews/:id/hide", controllers.HideReview)
+		admin.POST("/moderation/reviews/:id/restore", controllers.RestoreReview)
+		admin.POST("/moderation/reviews/:id/reply/hide", controllers.HideReviewReply)
+		admin.POST("/moderation/reviews/:id/reply/restore", controllers.RestoreReviewReply)
+		admin.GET("/moderation/employer-reviews", controllers.GetEmployerModerationQueue)
+		admin.POST("/moderation/employer-reviews/:id/hide", controllers.HideEmployerReview)
+		admin.POST("/moderation/employer-reviews/:id/restore", controllers.RestoreEmployerReview)
+		admin.GET("/jobs", controllers.GetJobRuns)
+		admin.POST("/contract-templates", controllers.CreateContractTemplate)
+		admin.GET("/contract-templates", controllers.GetContractTemplates)
+		admin.POST("/deliveries", controllers.PlanDelivery)
+		admin.GET("/deliveries", controllers.GetDeliveries)
+		admin.PUT("/deliveries/:id", controllers.RescheduleDelivery)
+		admin.POST("/deliveries/:id/status", controllers.UpdateDeliveryStatus)
+		admin.POST("/payments/:id/refund", controllers.RefundPayment)
+		admin.POST("/fee-rules", controllers.CreateFeeRule)
+		admin.GET("/fee-rules", controllers.GetFeeRules)
+		admin.GET("/payroll", controllers.GetSalaryRecords)
+		admin.GET("/ledger/accounts", controllers.GetLedgerAccounts)
+		admin.GET("/ledger/transactions", controllers.GetLedgerTransactions)
 	}
 }
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// A Monday
+	monday := time.Date(2024, time.January, 15, 10, 7, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", monday, time.Date(2024, time.January, 15, 10, 8, 0, 0, time.UTC)},
+		{"* * * * *", monday.Add(30 * time.Second), time.Date(2024, time.January, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", monday, time.Date(2024, time.January, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 */6 * * *", monday, time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)},
+		{"5/20 * * * *", monday, time.Date(2024, time.January, 15, 10, 25, 0, 0, time.UTC)},
+		{"10-20/5 * * * *", monday, time.Date(2024, time.January, 15, 10, 10, 0, 0, time.UTC)},
+		{"50-55 * * * *", monday, time.Date(2024, time.January, 15, 10, 50, 0, 0, time.UTC)},
+		{"0,30 9,17 * * *", monday, time.Date(2024, time.January, 15, 17, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", monday, time.Date(2024, time.January, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 6,0", monday, time.Date(2024, time.January, 20, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", monday, time.Date(2024, time.January, 21, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matching is enough
+		{"0 0 1 * 5", monday, time.Date(2024, time.January, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 16 * 5", monday, time.Date(2024, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		// One day field restricted: only that one counts
+		{"0 0 1 * *", monday, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 5", monday, time.Date(2024, time.January, 19, 0, 0, 0, 0, time.UTC)},
+		// Month and year rollover
+		{"0 0 31 * *", time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC), time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.December, 31, 23, 59, 0, 0, time.UTC), time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"59 23 * * *", time.Date(2024, time.December, 31, 23, 59, 0, 0, time.UTC), time.Date(2025, time.January, 1, 23, 59, 0, 0, time.UTC)},
+		{"30 2 29 2 *", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, time.February, 29, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 1,7 *", monday, time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		// Never matches
+		{"0 0 30 2 *", monday, time.Time{}},
+		// Shorthands
+		{"@hourly", monday, time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", monday, time.Date(2024, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", monday, time.Date(2024, time.January, 21, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", monday, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", monday, time.Date(2024, time.January, 15, 10, 8, 30, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		schedule, err := Parse(test.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.spec, err)
+			continue
+		}
+		if got := schedule.Next(test.from); !got.Equal(test.want) {
+			t.Errorf("Parse(%q).Next(%s) = %s, want %s", test.spec, test.from, got, test.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+		"@every 1ms",
+		"@every soon",
+	}
+
+	for _, spec := range specs {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
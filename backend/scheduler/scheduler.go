@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const defaultTimeout = 10 * time.Minute
+
+// Job is a unit of scheduled work
+type Job struct {
+	Name     string
+	Spec     string
+	Schedule Schedule
+	Run      func(ctx context.Context) error
+	// Timeout bounds a run and is how long the lock is held; a crashed
+	// instance's lock expires after it
+	Timeout time.Duration
+}
+
+// Scheduler runs jobs on their schedules. Several instances may run the same
+// scheduler: the job_runs collection makes sure each run happens once.
+type Scheduler struct {
+	jobs  []Job
+	owner string
+}
+
+func New() *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{owner: fmt.Sprintf("%s:%d", host, os.Getpid())}
+}
+
+// Add registers a job running fn on the schedule spec, see Parse
+func (s *Scheduler) Add(name, spec string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("job %s: %q never runs", name, spec)
+	}
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return fmt.Errorf("job %s is already registered", name)
+		}
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	s.jobs = append(s.jobs, Job{Name: name, Spec: spec, Schedule: schedule, Run: fn, Timeout: timeout})
+	return nil
+}
+
+// Start checks for due jobs every tick until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context, tick time.Duration) {
+	for _, job := range s.jobs {
+		if err := s.register(ctx, job); err != nil {
+			log.Printf("Error registering job %s: %v", job.Name, err)
+		}
+	}
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		for _, job := range s.jobs {
+			claimed, err := s.claim(ctx, job)
+			if err != nil {
+				log.Printf("Error claiming job %s: %v", job.Name, err)
+				continue
+			}
+			if claimed {
+				go s.run(ctx, job)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// register creates the job's state the first time it is seen, with its first
+// run at the next scheduled time, and records its current schedule
+func (s *Scheduler) register(ctx context.Context, job Job) error {
+	collection := config.DB.Collection("job_runs")
+	_, err := collection.InsertOne(ctx, models.JobRun{
+		Name:      job.Name,
+		Schedule:  job.Spec,
+		NextRunAt: job.Schedule.Next(time.Now()),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		_, err = collection.UpdateOne(ctx, bson.M{"_id": job.Name}, bson.M{"$set": bson.M{"schedule": job.Spec}})
+	}
+	return err
+}
+
+// claim locks the job when it is due and no other instance holds it
+func (s *Scheduler) claim(ctx context.Context, job Job) (bool, error) {
+	now := time.Now()
+	result, err := config.DB.Collection("job_runs").UpdateOne(ctx,
+		bson.M{
+			"_id":          job.Name,
+			"next_run_at":  bson.M{"$lte": now},
+			"locked_until": bson.M{"$lte": now},
+		},
+		bson.M{"$set": bson.M{"owner": s.owner, "locked_until": now.Add(job.Timeout)}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	started := time.Now()
+	runCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	err := job.Run(runCtx)
+	cancel()
+
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+		log.Printf("Error running job %s: %v", job.Name, err)
+	}
+
+	finished := time.Now()
+	_, dbErr := config.DB.Collection("job_runs").UpdateOne(ctx,
+		bson.M{"_id": job.Name, "owner": s.owner},
+		bson.M{"$set": bson.M{
+			"locked_until":  finished,
+			"next_run_at":   job.Schedule.Next(finished),
+			"last_run_at":   started,
+			"last_duration": finished.Sub(started).Round(time.Millisecond).String(),
+			"last_error":    lastError,
+		}},
+	)
+	if dbErr != nil {
+		log.Printf("Error recording run of job %s: %v", job.Name, dbErr)
+	}
+}
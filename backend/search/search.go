@@ -0,0 +1,60 @@
+package search
+
+import (
+	"backend/config"
+	"backend/models"
+	"context"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tokenize splits text into lowercase search keywords. Letters from any
+// script are kept, so Amharic names and locations are searchable too.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	seen := map[string]bool{}
+	var keywords []string
+	for _, field := range fields {
+		if !seen[field] {
+			seen[field] = true
+			keywords = append(keywords, field)
+		}
+	}
+	return keywords
+}
+
+// HousekeeperKeywords returns the keywords a housekeeper can be found by
+func HousekeeperKeywords(housekeeper models.Housekeeper) []string {
+	parts := []string{
+		housekeeper.Name,
+		housekeeper.Location,
+		housekeeper.PlaceOfBirth,
+		housekeeper.Religion,
+		strings.ReplaceAll(string(housekeeper.Category), "_", " "),
+		strings.ReplaceAll(string(housekeeper.EmploymentType), "_", " "),
+	}
+	parts = append(parts, housekeeper.Skills...)
+	parts = append(parts, housekeeper.Certifications...)
+
+	return Tokenize(strings.Join(parts, " "))
+}
+
+// IndexHousekeeper refreshes the search keywords stored on a housekeeper
+func IndexHousekeeper(ctx context.Context, housekeeperID primitive.ObjectID) error {
+	var housekeeper models.Housekeeper
+	if err := config.DB.Collection("housekeepers").FindOne(ctx, bson.M{"_id": housekeeperID}).Decode(&housekeeper); err != nil {
+		return err
+	}
+
+	_, err := config.DB.Collection("housekeepers").UpdateOne(ctx,
+		bson.M{"_id": housekeeperID},
+		bson.M{"$set": bson.M{"search_keywords": HousekeeperKeywords(housekeeper)}},
+	)
+	return err
+}
@@ -0,0 +1,183 @@
+package subscribers
+
+import (
+	"backend/config"
+	"backend/contracts"
+	"backend/events"
+	"backend/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func registerAudit(bus *events.Bus) {
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.HiringCreated) error {
+		return writeAudit(ctx, event, map[string]primitive.ObjectID{
+			"hiring":      event.Hiring.ID,
+			"employer":    event.Hiring.EmployerID,
+			"housekeeper": event.Hiring.HousekeeperID,
+		}, map[string]interface{}{"status": event.Hiring.Status})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.HiringStatusChanged) error {
+		return writeAudit(ctx, event, map[string]primitive.ObjectID{
+			"hiring":      event.Hiring.ID,
+			"employer":    event.Hiring.EmployerID,
+			"housekeeper": event.Hiring.HousekeeperID,
+		}, map[string]interface{}{"from": event.PreviousStatus, "to": event.Hiring.Status})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.TrialPassed) error {
+		return writeAudit(ctx, event, map[string]primitive.ObjectID{
+			"hiring":      event.Hiring.ID,
+			"employer":    event.Hiring.EmployerID,
+			"housekeeper": event.Hiring.HousekeeperID,
+		}, map[string]interface{}{"ends_at": event.Hiring.Trial.EndsAt})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.ContractSigned) error {
+		signature, _ := contracts.Signature(event.Contract, event.Party)
+		return writeAudit(ctx, event, map[string]primitive.ObjectID{
+			"contract":    event.Contract.ID,
+			"hiring":      event.Contract.HiringID,
+			"employer":    event.Contract.EmployerID,
+			"housekeeper": event.Contract.HousekeeperID,
+		}, map[string]interface{}{"party": event.Party, "version": event.Contract.Version, "hash": event.Contract.Hash, "ip": signature.IP})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.PaymentChanged) error {
+		return writeAudit(ctx, event, map[string]primitive.ObjectID{
+			"payment":  event.Payment.ID,
+			"hiring":   event.Payment.HiringID,
+			"employer": event.Payment.EmployerID,
+		}, map[string]interface{}{
+			"from":      event.PreviousStatus,
+			"to":        event.Payment.Status,
+			"amount":    event.Payment.Amount,
+			"provider":  event.Payment.Provider,
+			"reference": event.Payment.Reference,
+		})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.InvoiceIssued) error {
+		return writeAudit(ctx, event, map[string]primitive.ObjectID{
+			"invoice":  event.Invoice.ID,
+			"hiring":   event.Invoice.HiringID,
+			"employer": event.Invoice.EmployerID,
+		}, map[string]interface{}{"number": event.Invoice.Number, "total": event.Invoice.Total, "fee_rule": event.Invoice.FeeRuleID})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.SalaryRecordChanged) error {
+		record := event.Record
+		return writeAudit(ctx, event, map[string]primitive.ObjectID{
+			"salary_record": record.ID,
+			"hiring":        record.HiringID,
+			"employer":      record.EmployerID,
+			"housekeeper":   record.HousekeeperID,
+		}, map[string]interface{}{
+			"period":      record.Period,
+			"from":        event.PreviousStatus,
+			"to":          record.Status,
+			"net_pay":     record.NetPay,
+			"adjustments": len(record.Adjustments),
+			"paid_by":     record.PaidBy,
+		})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.OfferMade) error {
+		return writeAudit(ctx, event, offerRefs(event.Offer, event.Hiring), map[string]interface{}{
+			"proposed_by":  event.Offer.ProposedBy,
+			"salary_offer": event.Offer.Terms.SalaryOffer,
+		})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.OfferResponded) error {
+		return writeAudit(ctx, event, offerRefs(event.Offer, event.Hiring), map[string]interface{}{"status": event.Offer.Status})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.ReviewCreated) error {
+		return writeAudit(ctx, event, reviewRefs(event.Review), map[string]interface{}{"rating": event.Review.Rating})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.ReviewUpdated) error {
+		return writeAudit(ctx, event, reviewRefs(event.Review), map[string]interface{}{
+			"from_rating": event.Previous.Rating,
+			"to_rating":   event.Review.Rating,
+		})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.ReviewDeleted) error {
+		return writeAudit(ctx, event, reviewRefs(event.Review), map[string]interface{}{"rating": event.Review.Rating})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.ReviewReplied) error {
+		return writeAudit(ctx, event, reviewRefs(event.Review), nil)
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.ReviewModerated) error {
+		return writeAudit(ctx, event, reviewRefs(event.Review), map[string]interface{}{
+			"from": event.PreviousStatus,
+			"to":   event.Review.Status,
+			"note": event.Review.ModerationNote,
+		})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.EmployerReviewCreated) error {
+		return writeAudit(ctx, event, employerReviewRefs(event.Review), map[string]interface{}{"rating": event.Review.Rating})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.EmployerReviewUpdated) error {
+		return writeAudit(ctx, event, employerReviewRefs(event.Review), map[string]interface{}{
+			"from_rating": event.Previous.Rating,
+			"to_rating":   event.Review.Rating,
+		})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.EmployerReviewDeleted) error {
+		return writeAudit(ctx, event, employerReviewRefs(event.Review), map[string]interface{}{"rating": event.Review.Rating})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.EmployerReviewModerated) error {
+		return writeAudit(ctx, event, employerReviewRefs(event.Review), map[string]interface{}{
+			"from": event.PreviousStatus,
+			"to":   event.Review.Status,
+			"note": event.Review.ModerationNote,
+		})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.ReviewReplyModerated) error {
+		return writeAudit(ctx, event, reviewRefs(event.Review), map[string]interface{}{
+			"from": event.PreviousStatus,
+			"to":   event.Review.Reply.Status,
+			"note": event.Review.Reply.ModerationNote,
+		})
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.HousekeeperRegistered) error {
+		return writeAudit(ctx, event, map[string]primitive.ObjectID{
+			"housekeeper": event.Housekeeper.ID,
+		}, nil)
+	})
+	events.Subscribe(bus, "audit", func(ctx context.Context, event events.HousekeeperUpdated) error {
+		return writeAudit(ctx, event, map[string]primitive.ObjectID{
+			"housekeeper": event.Housekeeper.ID,
+		}, nil)
+	})
+}
+
+func reviewRefs(review models.Review) map[string]primitive.ObjectID {
+	return map[string]primitive.ObjectID{
+		"review":      review.ID,
+		"employer":    review.EmployerID,
+		"housekeeper": review.HousekeeperID,
+	}
+}
+
+func employerReviewRefs(review models.EmployerReview) map[string]primitive.ObjectID {
+	return map[string]primitive.ObjectID{
+		"employer_review": review.ID,
+		"employer":        review.EmployerID,
+		"housekeeper":     review.HousekeeperID,
+	}
+}
+
+func offerRefs(offer models.Offer, hiring models.Hiring) map[string]primitive.ObjectID {
+	return map[string]primitive.ObjectID{
+		"offer":       offer.ID,
+		"hiring":      hiring.ID,
+		"employer":    hiring.EmployerID,
+		"housekeeper": hiring.HousekeeperID,
+	}
+}
+
+func writeAudit(ctx context.Context, event events.Event, refs map[string]primitive.ObjectID, details map[string]interface{}) error {
+	_, err := config.DB.Collection("audit_logs").InsertOne(ctx, models.AuditLog{
+		Event:      event.EventName(),
+		Refs:       refs,
+		Details:    details,
+		OccurredAt: time.Now(),
+	})
+	return err
+}
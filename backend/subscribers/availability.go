@@ -0,0 +1,31 @@
+package subscribers
+
+import (
+	"backend/availability"
+	"backend/events"
+	"backend/models"
+	"context"
+)
+
+func registerAvailability(bus *events.Bus) {
+	// Runs inside the status change transaction, so an approval that would
+	// double-book the housekeeper is rolled back
+	events.Subscribe(bus, "availability", func(ctx context.Context, event events.HiringStatusChanged) error {
+		// Hourly bookings occupy the housekeeper only during their visits
+		if event.Hiring.BookingMode == models.HourlyBooking {
+			return nil
+		}
+
+		wasActive := availability.IsActive(event.PreviousStatus)
+		isActive := availability.IsActive(event.Hiring.Status)
+
+		switch {
+		case isActive && !wasActive:
+			return availability.Book(ctx, event.Hiring)
+		case wasActive && !isActive:
+			return availability.Release(ctx, event.Hiring)
+		default:
+			return nil
+		}
+	})
+}
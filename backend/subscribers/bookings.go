@@ -0,0 +1,36 @@
+package subscribers
+
+import (
+	"backend/availability"
+	"backend/bookings"
+	"backend/events"
+	"backend/models"
+	"context"
+)
+
+func registerBookings(bus *events.Bus) {
+	// Runs inside the status change transaction, so an approval whose visits
+	// clash with another booking is rolled back
+	events.Subscribe(bus, "bookings", func(ctx context.Context, event events.HiringStatusChanged) error {
+		hiring := event.Hiring
+		if hiring.BookingMode != models.HourlyBooking {
+			return nil
+		}
+
+		wasActive := availability.IsActive(event.PreviousStatus)
+		isActive := availability.IsActive(hiring.Status)
+
+		switch {
+		case isActive && !wasActive:
+			return bookings.Schedule(ctx, hiring)
+		case wasActive && !isActive:
+			by := "system"
+			if hiring.Cancellation != nil {
+				by = hiring.Cancellation.CancelledBy
+			}
+			return bookings.CancelRemaining(ctx, hiring, by)
+		default:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package subscribers
+
+import (
+	"backend/contracts"
+	"backend/events"
+	"backend/models"
+	"context"
+)
+
+func registerContracts(bus *events.Bus) {
+	// Rendering happens after the approval commits; a failure is retried by
+	// the relay instead of rolling the approval back
+	events.SubscribeAsync(bus, "contracts", func(ctx context.Context, event events.HiringStatusChanged) error {
+		hiring := event.Hiring
+		if hiring.Status != models.Approved || hiring.BookingMode == models.HourlyBooking {
+			return nil
+		}
+		return contracts.EnsureGenerated(ctx, hiring)
+	})
+}
@@ -0,0 +1,17 @@
+package subscribers
+
+import (
+	"backend/availability"
+	"backend/deliveries"
+	"backend/events"
+	"context"
+)
+
+func registerDeliveries(bus *events.Bus) {
+	events.Subscribe(bus, "deliveries", func(ctx context.Context, event events.HiringStatusChanged) error {
+		if availability.IsActive(event.PreviousStatus) && !availability.IsActive(event.Hiring.Status) {
+			return deliveries.Cancel(ctx, event.Hiring.ID)
+		}
+		return nil
+	})
+}
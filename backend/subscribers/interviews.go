@@ -0,0 +1,28 @@
+package subscribers
+
+import (
+	"backend/config"
+	"backend/events"
+	"backend/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func registerInterviews(bus *events.Bus) {
+	// Interviews only make sense while the hiring is being decided
+	events.Subscribe(bus, "interviews", func(ctx context.Context, event events.HiringStatusChanged) error {
+		if event.Hiring.Status == models.Pending {
+			return nil
+		}
+		_, err := config.DB.Collection("interviews").UpdateMany(ctx,
+			bson.M{
+				"hiring_id": event.Hiring.ID,
+				"status":    bson.M{"$in": models.OpenInterviewStatuses},
+			},
+			bson.M{"$set": bson.M{"status": models.InterviewCancelled, "updated_at": time.Now()}},
+		)
+		return err
+	})
+}
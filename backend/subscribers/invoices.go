@@ -0,0 +1,28 @@
+package subscribers
+
+import (
+	"backend/events"
+	"backend/invoices"
+	"backend/models"
+	"context"
+)
+
+func registerInvoices(bus *events.Bus) {
+	// The placement fee is invoiced on approval whether or not the payment
+	// can be set up; RequestPlacementFee finds the same invoice. A replacement
+	// takes over the payment of the placement it replaces and is only
+	// invoiced by RequestPlacementFee when there is none.
+	events.SubscribeAsync(bus, "invoices", func(ctx context.Context, event events.HiringStatusChanged) error {
+		hiring := event.Hiring
+		if hiring.Status != models.Approved || hiring.BookingMode == models.HourlyBooking || !hiring.ReplacementFor.IsZero() {
+			return nil
+		}
+		_, err := invoices.Issue(ctx, hiring)
+		return err
+	})
+	// The invoice follows its payment in the same transaction, so the two
+	// never disagree
+	events.Subscribe(bus, "invoices", func(ctx context.Context, event events.PaymentChanged) error {
+		return invoices.UpdateForPayment(ctx, event.Payment)
+	})
+}
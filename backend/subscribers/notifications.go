@@ -0,0 +1,350 @@
+package subscribers
+
+import (
+	"backend/contracts"
+	"backend/events"
+	"backend/models"
+	"backend/notifications"
+	"context"
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func registerNotifications(bus *events.Bus) {
+	events.SubscribeAsync(bus, "email", func(ctx context.Context, event events.HiringCreated) error {
+		return sendHiringEmail(event.Employer, event.Housekeeper, event.Hiring)
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.HiringCreated) error {
+		return notifications.NotifyUser(ctx, event.Hiring.HousekeeperID, event.EventName(), "New hiring request",
+			fmt.Sprintf("%s would like to hire you", event.Employer.Name),
+			map[string]interface{}{"hiring_id": event.Hiring.ID})
+	})
+	// SMS is a separate subscriber from push, so a failure of one channel
+	// neither stops the other nor resends it when the relay retries
+	events.SubscribeAsync(bus, "sms", func(ctx context.Context, event events.HiringCreated) error {
+		return notifications.SendSMS(ctx, event.Hiring.HousekeeperID, "housekeeper", event.EventName(),
+			fmt.Sprintf("AGAZH: %s sent you a hiring request. Salary offer: %.2f, start date: %s. Call AGAZH or open the app to respond.",
+				event.Employer.Name, event.Hiring.SalaryOffer, event.Hiring.StartDate.Format("2006-01-02")))
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.HiringStatusChanged) error {
+		hiring := event.Hiring
+		if hiring.Status == models.Cancelled && hiring.Cancellation != nil {
+			return notifyCancellation(ctx, event)
+		}
+		if hiring.Status == models.Terminated && hiring.Trial != nil && hiring.Trial.Replacement != nil {
+			return notifications.NotifyUser(ctx, hiring.HousekeeperID, "hiring.replaced", "Placement ended",
+				"The employer ended the placement during the trial period",
+				map[string]interface{}{"hiring_id": hiring.ID, "reason": hiring.Trial.Replacement.Reason})
+		}
+		if hiring.Status == models.Expired {
+			return notifications.NotifyUser(ctx, hiring.EmployerID, "hiring.expired", "Hiring request expired",
+				"Your hiring request expired without an answer. You can send a new one or choose another housekeeper.",
+				map[string]interface{}{"hiring_id": hiring.ID, "status": hiring.Status})
+		}
+
+		return notifications.NotifyUser(ctx, hiring.EmployerID, event.EventName(), "Hiring request updated",
+			fmt.Sprintf("Your hiring request is now %s", hiring.Status),
+			map[string]interface{}{"hiring_id": hiring.ID, "status": hiring.Status})
+	})
+	events.SubscribeAsync(bus, "sms", func(ctx context.Context, event events.HiringStatusChanged) error {
+		hiring := event.Hiring
+		switch {
+		case hiring.Status == models.Cancelled && hiring.Cancellation != nil:
+			// Only an approved hiring was something the other party was
+			// counting on
+			if event.PreviousStatus != models.Approved {
+				return nil
+			}
+			recipientID, recipientType := cancellationRecipient(hiring)
+			return notifications.SendSMS(ctx, recipientID, recipientType, "hiring.cancelled",
+				fmt.Sprintf("AGAZH: The %s cancelled the hiring starting %s. Open the app for details.",
+					hiring.Cancellation.CancelledBy, hiring.StartDate.Format("2006-01-02")))
+		case hiring.Status == models.Approved:
+			return notifications.SendSMS(ctx, hiring.EmployerID, "employer", "hiring.approved",
+				fmt.Sprintf("AGAZH: Your hiring request for a start on %s has been approved.", hiring.StartDate.Format("2006-01-02")))
+		}
+		return nil
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.TrialPassed) error {
+		return notifications.NotifyUser(ctx, event.Hiring.EmployerID, event.EventName(), "Trial period over",
+			"The trial period of your placement has ended", map[string]interface{}{"hiring_id": event.Hiring.ID})
+	})
+	// Each recipient has their own subscriber, so a retry after one send
+	// failed does not notify the other again
+	events.SubscribeAsync(bus, "housekeeper-notifications", func(ctx context.Context, event events.TrialPassed) error {
+		return notifications.NotifyUser(ctx, event.Hiring.HousekeeperID, event.EventName(), "Trial period passed",
+			"Congratulations, you passed the trial period of your placement", map[string]interface{}{"hiring_id": event.Hiring.ID})
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.DeliveryChanged) error {
+		delivery := event.Delivery
+		data := map[string]interface{}{"hiring_id": delivery.HiringID, "delivery_id": delivery.ID, "status": delivery.Status}
+		switch delivery.Status {
+		case models.DeliveryScheduled:
+			when := delivery.ScheduledAt.Format("2006-01-02 15:04")
+			body := fmt.Sprintf("Your housekeeper will be brought to %s on %s", delivery.DestinationAddress, when)
+			if delivery.Type == models.OfficePickup {
+				body = fmt.Sprintf("Please pick up your housekeeper at %s on %s", delivery.DestinationAddress, when)
+			}
+			return notifications.NotifyUser(ctx, delivery.EmployerID, event.EventName(), "Delivery scheduled", body, data)
+		case models.DeliveryEnRoute:
+			return notifications.NotifyUser(ctx, delivery.EmployerID, event.EventName(), "Housekeeper on the way",
+				"Your housekeeper is on the way", data)
+		case models.DeliveryDelivered:
+			return notifications.NotifyUser(ctx, delivery.EmployerID, event.EventName(), "Housekeeper delivered",
+				"Your housekeeper has been handed over. Welcome aboard!", data)
+		default:
+			return nil
+		}
+	})
+	events.SubscribeAsync(bus, "sms", func(ctx context.Context, event events.DeliveryChanged) error {
+		delivery := event.Delivery
+		if delivery.Status != models.DeliveryScheduled {
+			return nil
+		}
+		when := delivery.ScheduledAt.Format("2006-01-02 15:04")
+		body := fmt.Sprintf("AGAZH: Please be at %s on %s. %s will take you to your employer.", delivery.PickupAddress, when, delivery.StaffName)
+		if delivery.Type == models.OfficePickup {
+			body = fmt.Sprintf("AGAZH: Please be at %s on %s. %s will hand you over to your employer.", delivery.PickupAddress, when, delivery.StaffName)
+		}
+		return notifications.SendSMS(ctx, delivery.HousekeeperID, "housekeeper", "delivery.scheduled", body)
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.ContractGenerated) error {
+		return notifyContractReady(ctx, event, event.Contract.EmployerID)
+	})
+	events.SubscribeAsync(bus, "housekeeper-notifications", func(ctx context.Context, event events.ContractGenerated) error {
+		return notifyContractReady(ctx, event, event.Contract.HousekeeperID)
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.ContractSigned) error {
+		return notifyContractSigned(ctx, event, "employer", event.Contract.EmployerID)
+	})
+	events.SubscribeAsync(bus, "housekeeper-notifications", func(ctx context.Context, event events.ContractSigned) error {
+		return notifyContractSigned(ctx, event, "housekeeper", event.Contract.HousekeeperID)
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.PaymentChanged) error {
+		payment := event.Payment
+		data := map[string]interface{}{"hiring_id": payment.HiringID, "payment_id": payment.ID, "status": payment.Status}
+		switch {
+		case payment.Status == models.PaymentPending && event.PreviousStatus == "" && payment.CheckoutURL != "":
+			data["checkout_url"] = payment.CheckoutURL
+			return notifications.NotifyUser(ctx, payment.EmployerID, event.EventName(), "Placement fee due",
+				fmt.Sprintf("Please pay the placement fee of %s to confirm your placement", contracts.Money(payment.Amount)), data)
+		case payment.Status == models.PaymentHeld && event.PreviousStatus != models.PaymentHeld:
+			return notifications.NotifyUser(ctx, payment.EmployerID, event.EventName(), "Payment received",
+				fmt.Sprintf("We received your placement fee of %s. It is held until your trial period ends.", contracts.Money(payment.Amount)), data)
+		case payment.Status == models.PaymentFailed:
+			return notifications.NotifyUser(ctx, payment.EmployerID, event.EventName(), "Payment failed",
+				"Your placement fee payment did not go through. Please try again from the app.", data)
+		case payment.Status == models.PaymentRefunded:
+			return notifications.NotifyUser(ctx, payment.EmployerID, event.EventName(), "Payment refunded",
+				fmt.Sprintf("Your placement fee of %s has been refunded", contracts.Money(payment.Amount)), data)
+		}
+		return nil
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.InvoiceIssued) error {
+		invoice := event.Invoice
+		return notifications.NotifyUser(ctx, invoice.EmployerID, event.EventName(), "New invoice",
+			fmt.Sprintf("Invoice %s for %s is available in the app", invoice.Number, contracts.Money(invoice.Total)),
+			map[string]interface{}{"hiring_id": invoice.HiringID, "invoice_id": invoice.ID, "number": invoice.Number})
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.SalaryRecordChanged) error {
+		record := event.Record
+		data := map[string]interface{}{"hiring_id": record.HiringID, "period": record.Period, "status": record.Status}
+		month := record.PeriodStart.Format("January 2006")
+		switch {
+		case event.PreviousStatus == "":
+			return notifications.NotifyUser(ctx, record.EmployerID, event.EventName(), "Payroll period open",
+				fmt.Sprintf("Your housekeeper's %s salary comes to %s after tax and pension. Record any advances in the app and mark it paid once you have paid.",
+					month, contracts.Money(record.NetPay)), data)
+		case record.Status == models.SalaryPaid && event.PreviousStatus == models.SalaryUnpaid:
+			return notifications.NotifyUser(ctx, record.HousekeeperID, event.EventName(), "Salary paid",
+				fmt.Sprintf("Your %s salary of %s was paid. Your payslip is in the app.", month, contracts.Money(record.NetPay)), data)
+		}
+		return nil
+	})
+	events.SubscribeAsync(bus, "sms", func(ctx context.Context, event events.SalaryRecordChanged) error {
+		record := event.Record
+		if record.Status != models.SalaryPaid || event.PreviousStatus != models.SalaryUnpaid {
+			return nil
+		}
+		return notifications.SendSMS(ctx, record.HousekeeperID, "housekeeper", "salary.paid",
+			fmt.Sprintf("AGAZH: Your %s salary of %s was marked as paid. Call AGAZH if you did not receive it.",
+				record.PeriodStart.Format("January 2006"), contracts.Money(record.NetPay)))
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.OfferMade) error {
+		recipientID := event.Hiring.HousekeeperID
+		if event.Offer.ProposedBy == "housekeeper" {
+			recipientID = event.Hiring.EmployerID
+		}
+		return notifications.NotifyUser(ctx, recipientID, event.EventName(), "New offer",
+			fmt.Sprintf("The %s proposed a salary of %.2f", event.Offer.ProposedBy, event.Offer.Terms.SalaryOffer),
+			map[string]interface{}{"hiring_id": event.Hiring.ID, "offer_id": event.Offer.ID})
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.OfferResponded) error {
+		recipientID := event.Hiring.EmployerID
+		if event.Offer.ProposedBy == "housekeeper" {
+			recipientID = event.Hiring.HousekeeperID
+		}
+		return notifications.NotifyUser(ctx, recipientID, event.EventName(), "Offer answered",
+			fmt.Sprintf("Your offer was %s", strings.ToLower(string(event.Offer.Status))),
+			map[string]interface{}{"hiring_id": event.Hiring.ID, "offer_id": event.Offer.ID, "status": event.Offer.Status})
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.MessageSent) error {
+		recipientID := event.Hiring.HousekeeperID
+		if event.Message.SenderType == "housekeeper" {
+			recipientID = event.Hiring.EmployerID
+		}
+		return notifications.NotifyUser(ctx, recipientID, event.EventName(), "New message",
+			messagePreview(event.Message),
+			map[string]interface{}{"hiring_id": event.Hiring.ID, "message_id": event.Message.ID})
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.InterviewChanged) error {
+		interview := event.Interview
+		// Tell the party that did not make the change
+		recipientID := interview.HousekeeperID
+		if event.Actor == "housekeeper" {
+			recipientID = interview.EmployerID
+		}
+
+		var title, body string
+		switch interview.Status {
+		case models.InterviewProposed:
+			title, body = "Interview proposed", fmt.Sprintf("The %s proposed %d interview slot(s)", event.Actor, len(interview.Slots))
+		case models.InterviewConfirmed:
+			title, body = "Interview confirmed", fmt.Sprintf("Your interview is set for %s", interview.ScheduledAt.Format("2006-01-02 15:04"))
+		case models.InterviewCancelled:
+			title, body = "Interview cancelled", fmt.Sprintf("The %s cancelled the interview", event.Actor)
+		case models.InterviewCompleted:
+			title, body = "Interview outcome", fmt.Sprintf("Your interview outcome: %s", interview.Outcome)
+		default:
+			return nil
+		}
+
+		return notifications.NotifyUser(ctx, recipientID, event.EventName(), title, body,
+			map[string]interface{}{"hiring_id": interview.HiringID, "interview_id": interview.ID, "status": interview.Status})
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.OccurrenceChanged) error {
+		occurrence := event.Occurrence
+		if occurrence.Status == models.OccurrenceCompleted {
+			return nil
+		}
+
+		recipientID := occurrence.HousekeeperID
+		if occurrence.ChangedBy == "housekeeper" {
+			recipientID = occurrence.EmployerID
+		}
+		return notifications.NotifyUser(ctx, recipientID, event.EventName(), "Visit "+strings.ToLower(string(occurrence.Status)),
+			fmt.Sprintf("The visit on %s will not take place", occurrence.StartsAt.Format("2006-01-02 15:04")),
+			map[string]interface{}{"hiring_id": occurrence.HiringID, "occurrence_id": occurrence.ID, "status": occurrence.Status})
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.ReviewCreated) error {
+		return notifications.NotifyUser(ctx, event.Review.HousekeeperID, event.EventName(), "New review",
+			fmt.Sprintf("You received a %.0f-star review", event.Review.Rating),
+			map[string]interface{}{"review_id": event.Review.ID})
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.ReviewReplied) error {
+		return notifications.NotifyUser(ctx, event.Review.EmployerID, event.EventName(), "Reply to your review",
+			"The housekeeper you reviewed has replied",
+			map[string]interface{}{"review_id": event.Review.ID})
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, event events.EmployerReviewCreated) error {
+		return notifications.NotifyUser(ctx, event.Review.EmployerID, event.EventName(), "New review",
+			fmt.Sprintf("A housekeeper gave you a %.0f-star review", event.Review.Rating),
+			map[string]interface{}{"employer_review_id": event.Review.ID})
+	})
+}
+
+// notifyContractReady asks one party of a contract to sign it
+func notifyContractReady(ctx context.Context, event events.ContractGenerated, recipientID primitive.ObjectID) error {
+	contract := event.Contract
+	return notifications.NotifyUser(ctx, recipientID, event.EventName(), "Contract ready",
+		"Your employment contract is ready. Please read and sign it in the app.",
+		map[string]interface{}{"hiring_id": contract.HiringID, "version": contract.Version})
+}
+
+// notifyContractSigned tells party, the employer or the housekeeper of a
+// contract, that the other party signed it, or that both have once it is
+// fully signed. A party is not told about their own signature.
+func notifyContractSigned(ctx context.Context, event events.ContractSigned, party string, recipientID primitive.ObjectID) error {
+	contract := event.Contract
+	data := map[string]interface{}{"hiring_id": contract.HiringID, "version": contract.Version}
+	if contract.Status == models.ContractSigned {
+		return notifications.NotifyUser(ctx, recipientID, event.EventName(), "Contract signed",
+			"Both parties have signed the employment contract. You can download it in the app.", data)
+	}
+	if event.Party == party {
+		return nil
+	}
+	return notifications.NotifyUser(ctx, recipientID, event.EventName(), "Contract signed",
+		fmt.Sprintf("The %s has signed the employment contract. Please sign it too.", event.Party), data)
+}
+
+// notifyCancellation tells the other party that a hiring was cancelled
+func notifyCancellation(ctx context.Context, event events.HiringStatusChanged) error {
+	hiring := event.Hiring
+	recipientID, _ := cancellationRecipient(hiring)
+	return notifications.NotifyUser(ctx, recipientID, "hiring.cancelled", "Hiring cancelled",
+		fmt.Sprintf("The %s cancelled the hiring starting %s", hiring.Cancellation.CancelledBy, hiring.StartDate.Format("2006-01-02")),
+		map[string]interface{}{"hiring_id": hiring.ID, "reason": hiring.Cancellation.Reason})
+}
+
+// cancellationRecipient returns the party of a cancelled hiring that did not
+// cancel it
+func cancellationRecipient(hiring models.Hiring) (primitive.ObjectID, string) {
+	if hiring.Cancellation.CancelledBy == "housekeeper" {
+		return hiring.EmployerID, "employer"
+	}
+	return hiring.HousekeeperID, "housekeeper"
+}
+
+// messagePreview shortens a message for a push notification
+func messagePreview(message models.Message) string {
+	if message.Body == "" {
+		return "Sent an attachment"
+	}
+	runes := []rune(message.Body)
+	if len(runes) > 100 {
+		return string(runes[:100]) + "…"
+	}
+	return message.Body
+}
+
+func sendHiringEmail(employer models.Employer, housekeeper models.Housekeeper, hiring models.Hiring) error {
+	from := os.Getenv("SMTP_USERNAME")
+	password := os.Getenv("SMTP_PASSWORD")
+	to := os.Getenv("ADMIN_EMAIL")
+	smtpHost := os.Getenv("SMTP_HOST")
+	smtpPort := os.Getenv("SMTP_PORT")
+
+	message := fmt.Sprintf(`
+		Subject: New Hiring Request
+
+		Employer Details:
+		Name: %s
+		Email: %s
+		Phone: %s
+		Address: %s
+		Family Size: %d
+
+		Housekeeper Details:
+		Name: %s
+		Category: %s
+		Employment Type: %s
+		Experience: %d years
+
+		Hiring Details:
+		Salary offered: $%.2f
+		Start Date: %s
+		Delivery Type: %s
+		Requirements: %s
+	`,
+		employer.Name, employer.Email, employer.PhoneNumber, employer.Address, employer.FamilySize,
+		housekeeper.Name, housekeeper.Category, housekeeper.EmploymentType, housekeeper.Experience,
+		hiring.SalaryOffer, hiring.StartDate.Format("2006-01-02"), hiring.DeliveryType, hiring.Requirements)
+
+	auth := smtp.PlainAuth("", from, password, smtpHost)
+	return smtp.SendMail(smtpHost+":"+smtpPort, auth, from, []string{to}, []byte(message))
+}
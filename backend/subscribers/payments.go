@@ -0,0 +1,30 @@
+package subscribers
+
+import (
+	"backend/events"
+	"backend/models"
+	"backend/payments"
+	"context"
+)
+
+func registerPayments(bus *events.Bus) {
+	// Payments talk to the provider, so they run after the hiring change
+	// commits and are retried by the relay when the provider is unavailable
+	events.SubscribeAsync(bus, "payments", func(ctx context.Context, event events.HiringStatusChanged) error {
+		hiring := event.Hiring
+		if hiring.BookingMode == models.HourlyBooking {
+			return nil
+		}
+
+		switch hiring.Status {
+		case models.Approved:
+			return payments.RequestPlacementFee(ctx, hiring)
+		case models.Cancelled, models.Completed, models.Terminated:
+			return payments.SettleEndedHiring(ctx, hiring)
+		}
+		return nil
+	})
+	events.SubscribeAsync(bus, "payments", func(ctx context.Context, event events.TrialPassed) error {
+		return payments.Release(ctx, event.Hiring.ID)
+	})
+}
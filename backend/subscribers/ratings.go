@@ -0,0 +1,45 @@
+package subscribers
+
+import (
+	"backend/events"
+	"backend/ratings"
+	"context"
+)
+
+func registerRatings(bus *events.Bus) {
+	events.Subscribe(bus, "ratings", func(ctx context.Context, event events.ReviewCreated) error {
+		return ratings.Apply(ctx, event.Review.HousekeeperID, nil, &event.Review)
+	})
+	events.Subscribe(bus, "ratings", func(ctx context.Context, event events.ReviewUpdated) error {
+		previous := event.Review
+		previous.Rating = event.Previous.Rating
+		previous.Scores = event.Previous.Scores
+		previous.Status = event.PreviousStatus
+		return ratings.Apply(ctx, event.Review.HousekeeperID, &previous, &event.Review)
+	})
+	events.Subscribe(bus, "ratings", func(ctx context.Context, event events.ReviewDeleted) error {
+		return ratings.Apply(ctx, event.Review.HousekeeperID, &event.Review, nil)
+	})
+	events.Subscribe(bus, "ratings", func(ctx context.Context, event events.ReviewModerated) error {
+		previous := event.Review
+		previous.Status = event.PreviousStatus
+		return ratings.Apply(ctx, event.Review.HousekeeperID, &previous, &event.Review)
+	})
+	events.Subscribe(bus, "ratings", func(ctx context.Context, event events.EmployerReviewCreated) error {
+		return ratings.ApplyEmployer(ctx, event.Review.EmployerID, nil, &event.Review)
+	})
+	events.Subscribe(bus, "ratings", func(ctx context.Context, event events.EmployerReviewUpdated) error {
+		previous := event.Review
+		previous.Rating = event.Previous.Rating
+		previous.Status = event.PreviousStatus
+		return ratings.ApplyEmployer(ctx, event.Review.EmployerID, &previous, &event.Review)
+	})
+	events.Subscribe(bus, "ratings", func(ctx context.Context, event events.EmployerReviewDeleted) error {
+		return ratings.ApplyEmployer(ctx, event.Review.EmployerID, &event.Review, nil)
+	})
+	events.Subscribe(bus, "ratings", func(ctx context.Context, event events.EmployerReviewModerated) error {
+		previous := event.Review
+		previous.Status = event.PreviousStatus
+		return ratings.ApplyEmployer(ctx, event.Review.EmployerID, &previous, &event.Review)
+	})
+}
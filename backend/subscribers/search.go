@@ -0,0 +1,16 @@
+package subscribers
+
+import (
+	"backend/events"
+	"backend/search"
+	"context"
+)
+
+func registerSearch(bus *events.Bus) {
+	events.Subscribe(bus, "search", func(ctx context.Context, event events.HousekeeperRegistered) error {
+		return search.IndexHousekeeper(ctx, event.Housekeeper.ID)
+	})
+	events.Subscribe(bus, "search", func(ctx context.Context, event events.HousekeeperUpdated) error {
+		return search.IndexHousekeeper(ctx, event.Housekeeper.ID)
+	})
+}
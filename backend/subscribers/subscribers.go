@@ -0,0 +1,19 @@
+package subscribers
+
+import "backend/events"
+
+// Register attaches every side effect of the domain events to bus
+func Register(bus *events.Bus) {
+	registerRatings(bus)
+	registerSearch(bus)
+	registerInterviews(bus)
+	registerAvailability(bus)
+	registerBookings(bus)
+	registerDeliveries(bus)
+	registerContracts(bus)
+	registerPayments(bus)
+	registerInvoices(bus)
+	registerAudit(bus)
+	registerNotifications(bus)
+	registerWebhooks(bus)
+}
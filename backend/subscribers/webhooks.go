@@ -0,0 +1,22 @@
+package subscribers
+
+import (
+	"backend/events"
+	"backend/webhooks"
+	"context"
+)
+
+func registerWebhooks(bus *events.Bus) {
+	events.SubscribeAsync(bus, "webhooks", func(ctx context.Context, event events.HiringCreated) error {
+		return webhooks.Dispatch(ctx, webhooks.HiringCreated, event.Hiring)
+	})
+	events.SubscribeAsync(bus, "webhooks", func(ctx context.Context, event events.HiringStatusChanged) error {
+		return webhooks.Dispatch(ctx, webhooks.HiringStatusChanged, event.Hiring)
+	})
+	events.SubscribeAsync(bus, "webhooks", func(ctx context.Context, event events.ReviewCreated) error {
+		return webhooks.Dispatch(ctx, webhooks.ReviewCreated, event.Review)
+	})
+	events.SubscribeAsync(bus, "webhooks", func(ctx context.Context, event events.HousekeeperUpdated) error {
+		return webhooks.Dispatch(ctx, webhooks.HousekeeperUpdated, event.Housekeeper)
+	})
+}
@@ -0,0 +1,238 @@
+package webhooks
+
+import (
+	"backend/config"
+	"backend/models"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Event types partners can subscribe to
+const (
+	HiringCreated       = "hiring.created"
+	HiringStatusChanged = "hiring.status_changed"
+	ReviewCreated       = "review.created"
+	HousekeeperUpdated  = "housekeeper.updated"
+)
+
+var EventTypes = []string{HiringCreated, HiringStatusChanged, ReviewCreated, HousekeeperUpdated}
+
+const (
+	SignatureHeader = "X-Agazh-Signature"
+	EventHeader     = "X-Agazh-Event"
+	DeliveryHeader  = "X-Agazh-Delivery"
+
+	maxAttempts  = 6
+	initialDelay = 10 * time.Second
+	// claimLease is how long a delivery being posted is hidden from other
+	// attempts; it outlasts the request timeout
+	claimLease = time.Minute
+)
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Payload is the JSON body posted to subscribers
+type Payload struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// IsEventType reports whether name is an event type partners can subscribe to
+func IsEventType(name string) bool {
+	for _, eventType := range EventTypes {
+		if eventType == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateSecret returns a random signing secret for a new subscription
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+// Sign computes the signature header value for body. Receivers recompute the
+// HMAC-SHA256 of "<timestamp>.<body>" with their secret and compare it to v1.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Dispatch sends an event to every active subscription interested in it.
+// Deliveries are stored before the first attempt, and failed ones are retried
+// with exponential backoff by RetryDeliveries.
+func Dispatch(ctx context.Context, eventType string, data interface{}) error {
+	cursor, err := config.DB.Collection("webhook_subscriptions").Find(ctx, bson.M{
+		"active":      true,
+		"event_types": eventType,
+	})
+	if err != nil {
+		return err
+	}
+
+	var subscriptions []models.WebhookSubscription
+	if err := cursor.All(ctx, &subscriptions); err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload := Payload{
+		ID:        primitive.NewObjectID().Hex(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var ids []primitive.ObjectID
+	for _, subscription := range subscriptions {
+		now := time.Now()
+		delivery := models.WebhookDelivery{
+			ID:             primitive.NewObjectID(),
+			SubscriptionID: subscription.ID,
+			EventID:        payload.ID,
+			EventType:      eventType,
+			Payload:        string(body),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+		if _, err := config.DB.Collection("webhook_deliveries").InsertOne(ctx, delivery); err != nil {
+			return err
+		}
+		ids = append(ids, delivery.ID)
+	}
+
+	// The first attempt is made right away; a failure is left to the
+	// scheduled retries
+	for _, id := range ids {
+		if _, err := attempt(ctx, bson.M{"_id": id}); err != nil {
+			log.Printf("Error delivering webhook %s: %v", id.Hex(), err)
+		}
+	}
+	return nil
+}
+
+// RetryDeliveries tries every pending delivery that is due again
+func RetryDeliveries(ctx context.Context) error {
+	for {
+		found, err := attempt(ctx, bson.M{})
+		if err != nil {
+			return err
+		}
+		if !found {
+			return nil
+		}
+	}
+}
+
+// attempt claims one due pending delivery matching filter, posts it and
+// records the outcome. It reports whether a delivery was claimed.
+func attempt(ctx context.Context, filter bson.M) (bool, error) {
+	collection := config.DB.Collection("webhook_deliveries")
+
+	// Pushing next_attempt_at past the request timeout claims the delivery,
+	// so another instance does not post it at the same time
+	now := time.Now()
+	filter["status"] = models.WebhookDeliveryPending
+	filter["next_attempt_at"] = bson.M{"$lte": now}
+	var delivery models.WebhookDelivery
+	err := collection.FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(claimLease)}},
+	).Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	attempts := delivery.Attempts + 1
+	update := bson.M{
+		"attempts":        attempts,
+		"last_attempt_at": time.Now(),
+		"error":           "",
+	}
+
+	var subscription models.WebhookSubscription
+	err = config.DB.Collection("webhook_subscriptions").FindOne(ctx, bson.M{"_id": delivery.SubscriptionID}).Decode(&subscription)
+	if err == mongo.ErrNoDocuments {
+		update["status"] = models.WebhookDeliveryFailed
+		update["error"] = "subscription was deleted"
+	} else if err != nil {
+		return true, err
+	} else {
+		status, err := post(ctx, subscription, delivery)
+		update["response_status"] = status
+		switch {
+		case err == nil:
+			update["status"] = models.WebhookDeliverySucceeded
+		case attempts >= maxAttempts:
+			update["status"] = models.WebhookDeliveryFailed
+			update["error"] = err.Error()
+		default:
+			update["error"] = err.Error()
+			update["next_attempt_at"] = time.Now().Add(initialDelay << (attempts - 1))
+		}
+	}
+
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": delivery.ID}, bson.M{"$set": update})
+	return true, err
+}
+
+func post(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AGAZH-Webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, time.Now().Unix(), body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}